@@ -0,0 +1,414 @@
+package cmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+var (
+	backupDir string
+	noBackup  bool
+)
+
+const (
+	snapshotVersion = 1
+	backupKeyFile   = "backup.key"
+)
+
+// snapshot is the on-disk format of a backup taken before a destructive operation
+type snapshot struct {
+	Version    int             `json:"version"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Operation  string          `json:"operation"`
+	Region     string          `json:"region,omitempty"`
+	Parameters []snapshotParam `json:"parameters"`
+}
+
+type snapshotParam struct {
+	Name           string            `json:"name"`
+	Type           string            `json:"type"`
+	Value          string            `json:"value,omitempty"`
+	EncryptedValue string            `json:"encrypted_value,omitempty"`
+	KeyID          string            `json:"key_id,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	Tier           string            `json:"tier,omitempty"`
+	DataType       string            `json:"data_type,omitempty"`
+	AllowedPattern string            `json:"allowed_pattern,omitempty"`
+	Policies       string            `json:"policies,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+}
+
+// defaultBackupDir returns the user config dir based location for snapshots
+func defaultBackupDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, Name, "backups")
+}
+
+func resolveBackupDir() string {
+	if backupDir != "" {
+		return backupDir
+	}
+	return defaultBackupDir()
+}
+
+// backupKeyPath returns where the snapshot key lives: AWS_SSM_BACKUP_KEY, or the
+// user config dir. It is never kept with the snapshots, so a copied or shared
+// backup directory doesn't carry the means to decrypt it.
+func backupKeyPath() string {
+	if file := os.Getenv("AWS_SSM_BACKUP_KEY"); file != "" {
+		return file
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, Name, backupKeyFile)
+}
+
+// insideDir reports whether file is dir itself or somewhere below it
+func insideDir(file, dir string) bool {
+	absFile, err1 := filepath.Abs(file)
+	absDir, err2 := filepath.Abs(dir)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absFile)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// backupParameters snapshots names (skipping the ones that don't exist) before
+// operation destroys or replaces them. It returns the snapshot path, or "" when
+// backups are disabled or there was nothing to save.
+func backupParameters(operation string, names []string, client *ssm.Client) (string, error) {
+	if noBackup || len(names) == 0 {
+		return "", nil
+	}
+
+	params, err := collectSnapshotParams(names, client)
+	if err != nil {
+		return "", fmt.Errorf("failed to back up parameters: %w", err)
+	}
+	if len(params) == 0 {
+		return "", nil
+	}
+
+	dir := resolveBackupDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	keyFile := backupKeyPath()
+	if insideDir(keyFile, dir) {
+		return "", fmt.Errorf("backup key %s must not be inside the backup directory %s (see AWS_SSM_BACKUP_KEY)", keyFile, dir)
+	}
+	key, err := loadBackupKey(keyFile, true)
+	if err != nil {
+		return "", err
+	}
+	for i, p := range params {
+		if p.Type != string(types.ParameterTypeSecureString) {
+			continue
+		}
+		sealed, err := sealValue(key, p.Value)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt %s: %w", p.Name, err)
+		}
+		params[i].Value = ""
+		params[i].EncryptedValue = sealed
+	}
+
+	snap := snapshot{
+		Version:    snapshotVersion,
+		CreatedAt:  time.Now().UTC(),
+		Operation:  operation,
+		Region:     client.Options().Region,
+		Parameters: params,
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, fmt.Sprintf("%s-%s.json", snap.CreatedAt.Format("20060102T150405.000Z"), operation))
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	return file, nil
+}
+
+// takeBackup runs backupParameters and tells the user where the snapshot went
+func takeBackup(operation string, names []string, client *ssm.Client) error {
+	file, err := backupParameters(operation, names, client)
+	if err != nil {
+		return fmt.Errorf("%w (use --no-backup to skip)", err)
+	}
+	if file != "" && !emit(event{Operation: "backup", Path: file, Result: resultOK, Data: map[string]string{"for": operation}}) {
+		fmt.Printf("%s Backup written to %s\n", icon(markBackup), file)
+	}
+	return nil
+}
+
+// collectSnapshotParams gathers values, metadata and tags for the existing parameters in names
+func collectSnapshotParams(names []string, client *ssm.Client) ([]snapshotParam, error) {
+	byName := make(map[string]*snapshotParam)
+
+	for start := 0; start < len(names); start += ssmBatchSize {
+		batch := names[start:min(start+ssmBatchSize, len(names))]
+		out, err := client.GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          batch,
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range out.Parameters {
+			byName[*p.Name] = &snapshotParam{
+				Name:     *p.Name,
+				Type:     string(p.Type),
+				Value:    aws.ToString(p.Value),
+				DataType: aws.ToString(p.DataType),
+			}
+		}
+	}
+	if len(byName) == 0 {
+		return nil, nil
+	}
+
+	existing := make([]string, 0, len(byName))
+	for name := range byName {
+		existing = append(existing, name)
+	}
+	sort.Strings(existing)
+
+	meta, err := describeParametersByName(existing, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range meta {
+		p, ok := byName[aws.ToString(m.Name)]
+		if !ok {
+			continue
+		}
+		p.KeyID = aws.ToString(m.KeyId)
+		p.Description = aws.ToString(m.Description)
+		p.Tier = string(m.Tier)
+		p.AllowedPattern = aws.ToString(m.AllowedPattern)
+		p.Policies = policiesJSON(m.Policies)
+	}
+
+	for _, name := range existing {
+		out, err := client.ListTagsForResource(ctx, &ssm.ListTagsForResourceInput{
+			ResourceType: types.ResourceTypeForTaggingParameter,
+			ResourceId:   aws.String(name),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(out.TagList) > 0 {
+			byName[name].Tags = make(map[string]string, len(out.TagList))
+			for _, t := range out.TagList {
+				byName[name].Tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+			}
+		}
+	}
+
+	params := make([]snapshotParam, 0, len(existing))
+	for _, name := range existing {
+		params = append(params, *byName[name])
+	}
+	return params, nil
+}
+
+// describeParametersByName returns DescribeParameters metadata for the given names
+func describeParametersByName(names []string, client *ssm.Client) ([]types.ParameterMetadata, error) {
+	// The Name filter accepts at most 50 values
+	const filterBatch = 50
+
+	var result []types.ParameterMetadata
+	for start := 0; start < len(names); start += filterBatch {
+		batch := names[start:min(start+filterBatch, len(names))]
+		meta, err := describeParameters([]types.ParameterStringFilter{{
+			Key:    aws.String("Name"),
+			Option: aws.String("Equals"),
+			Values: batch,
+		}}, client)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, meta...)
+	}
+	return result, nil
+}
+
+// policiesJSON joins inline policies back into the array accepted by PutParameter
+func policiesJSON(policies []types.ParameterInlinePolicy) string {
+	if len(policies) == 0 {
+		return ""
+	}
+	texts := make([]string, 0, len(policies))
+	for _, p := range policies {
+		texts = append(texts, aws.ToString(p.PolicyText))
+	}
+	return "[" + strings.Join(texts, ",") + "]"
+}
+
+// putSnapshotParam writes p back to Parameter Store with all its metadata. key is only
+// needed when the value is sealed.
+func putSnapshotParam(p snapshotParam, key []byte, overwrite bool, client *ssm.Client) error {
+	value := p.Value
+	if p.EncryptedValue != "" {
+		var err error
+		value, err = openValue(key, p.EncryptedValue)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt snapshot value: %w", err)
+		}
+	}
+
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(p.Name),
+		Value:     aws.String(value),
+		Type:      types.ParameterType(p.Type),
+		Overwrite: aws.Bool(overwrite),
+	}
+	if p.KeyID != "" && input.Type == types.ParameterTypeSecureString {
+		input.KeyId = aws.String(p.KeyID)
+	}
+	if p.Description != "" {
+		input.Description = aws.String(p.Description)
+	}
+	if p.Tier != "" {
+		input.Tier = types.ParameterTier(p.Tier)
+	}
+	if p.DataType != "" {
+		input.DataType = aws.String(p.DataType)
+	}
+	if p.AllowedPattern != "" {
+		input.AllowedPattern = aws.String(p.AllowedPattern)
+	}
+	if p.Policies != "" {
+		input.Policies = aws.String(p.Policies)
+	}
+
+	if _, err := client.PutParameter(ctx, input); err != nil {
+		return err
+	}
+
+	return tagParameter(p.Name, p.Tags, client)
+}
+
+// tagParameter adds tags to an existing parameter. Tags can't be sent together with
+// Overwrite in PutParameter, so they are always applied separately.
+func tagParameter(name string, tags map[string]string, client *ssm.Client) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	ssmTags := make([]types.Tag, 0, len(tags))
+	for _, k := range sortedKeys(tags) {
+		ssmTags = append(ssmTags, types.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	_, err := client.AddTagsToResource(ctx, &ssm.AddTagsToResourceInput{
+		ResourceType: types.ResourceTypeForTaggingParameter,
+		ResourceId:   aws.String(name),
+		Tags:         ssmTags,
+	})
+	return err
+}
+
+func readSnapshot(file string) (*snapshot, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", file, err)
+	}
+	if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	return &snap, nil
+}
+
+// loadBackupKey reads the local AES key used for SecureString values in snapshots,
+// creating it on first use when create is set
+func loadBackupKey(file string, create bool) ([]byte, error) {
+	key, err := os.ReadFile(file)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("backup key %s is corrupt", file)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) || !create {
+		return nil, fmt.Errorf("failed to read backup key: %w", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup key directory: %w", err)
+	}
+	if err := os.WriteFile(file, key, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write backup key: %w", err)
+	}
+	return key, nil
+}
+
+func sealValue(key []byte, plain string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plain), nil)), nil
+}
+
+func openValue(key []byte, sealed string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
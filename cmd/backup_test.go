@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInsideDir(t *testing.T) {
+	tests := []struct {
+		file, dir string
+		want      bool
+	}{
+		{"/home/u/.config/aws-ssm/backups/backup.key", "/home/u/.config/aws-ssm/backups", true},
+		{"/home/u/.config/aws-ssm/backup.key", "/home/u/.config/aws-ssm/backups", false},
+		{"/home/u/.config/aws-ssm/backup.key", "/home/u/.config/aws-ssm", true},
+		{"/home/u/.config/aws-ssm/backups-old/backup.key", "/home/u/.config/aws-ssm/backups", false},
+		{"/srv/keys/..backup.key", "/srv/keys", true},
+	}
+	for _, tt := range tests {
+		if got := insideDir(tt.file, tt.dir); got != tt.want {
+			t.Errorf("insideDir(%q, %q) = %v, want %v", tt.file, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestSnapshotKey(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_SSM_BACKUP_KEY", filepath.Join(dir, "config", backupKeyFile))
+
+	current, err := loadBackupKey(backupKeyPath(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyDir := filepath.Join(dir, "backups")
+	legacy, err := loadBackupKey(filepath.Join(legacyDir, backupKeyFile), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seal := func(key []byte) []snapshotParam {
+		sealed, err := sealValue(key, "s3cret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []snapshotParam{{Name: "/plain", Value: "x"}, {Name: "/secret", EncryptedValue: sealed}}
+	}
+	snapshot := filepath.Join(legacyDir, "snapshot.json")
+
+	tests := []struct {
+		name   string
+		params []snapshotParam
+		want   []byte
+	}{
+		{"nothing sealed", []snapshotParam{{Name: "/plain", Value: "x"}}, nil},
+		{"current key", seal(current), current},
+		{"legacy key next to the snapshot", seal(legacy), legacy},
+	}
+	for _, tt := range tests {
+		got, err := snapshotKey(snapshot, tt.params)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != string(tt.want) {
+			t.Errorf("%s: picked the wrong key", tt.name)
+		}
+	}
+
+	if err := os.Remove(filepath.Join(legacyDir, backupKeyFile)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := snapshotKey(snapshot, seal(legacy)); err == nil {
+		t.Error("unknown key: expected an error")
+	}
+}
@@ -0,0 +1,746 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var browsePrefix string
+
+// browseDetailsHeight is the number of screen rows reserved for the details pane
+const browseDetailsHeight = 14
+
+var browseCmd = &cobra.Command{
+	Use:     "browse",
+	Short:   "Interactively browse, inspect and edit parameters in a full-screen view",
+	Aliases: []string{"b", "ui"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if jsonOutput() {
+			return fmt.Errorf("browse is interactive and does not support --output json")
+		}
+		if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+			return fmt.Errorf("browse needs an interactive terminal")
+		}
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		root := "/" + strings.Trim(browsePrefix, "/")
+		b := newBrowser(root, client)
+		if err := b.expand(b.root); err != nil {
+			return err
+		}
+		return b.run()
+	},
+}
+
+func init() {
+	browseCmd.Flags().StringVarP(&browsePrefix, "prefix", "p", "/", "SSM path prefix to start from")
+}
+
+type browseNode struct {
+	name      string
+	path      string
+	parent    *browseNode
+	children  map[string]*browseNode
+	isParam   bool
+	paramType types.ParameterType
+	expanded  bool
+	loaded    bool
+}
+
+type browseDetails struct {
+	meta     *types.ParameterMetadata
+	history  []types.ParameterHistory
+	value    string
+	revealed bool
+	err      string
+}
+
+type browseResult struct {
+	path    string
+	details *browseDetails
+}
+
+// browsePrompt is an in-progress line input or y/N confirmation at the bottom of the screen
+type browsePrompt struct {
+	label   string
+	input   string
+	confirm bool
+	done    func(b *browser, input string)
+}
+
+type browser struct {
+	client   *ssm.Client
+	root     *browseNode
+	cursor   int
+	offset   int
+	details  map[string]*browseDetails
+	loading  map[string]bool
+	results  chan browseResult
+	prompt   *browsePrompt
+	status   string
+	quitting bool
+}
+
+func newBrowser(rootPath string, client *ssm.Client) *browser {
+	return &browser{
+		client: client,
+		root: &browseNode{
+			name:     rootPath,
+			path:     rootPath,
+			children: make(map[string]*browseNode),
+		},
+		details: make(map[string]*browseDetails),
+		loading: make(map[string]bool),
+		results: make(chan browseResult, 8),
+	}
+}
+
+// expand opens n. The first expand lists the names below n once, and every
+// branch under it then opens from that skeleton without further calls.
+func (b *browser) expand(n *browseNode) error {
+	if !n.loaded {
+		if err := b.load(n); err != nil {
+			return err
+		}
+	}
+	n.expanded = true
+	return nil
+}
+
+// load fills in the whole skeleton below n from DescribeParameters, which returns
+// names and types only. Values are read when a parameter is selected.
+func (b *browser) load(n *browseNode) error {
+	var filters []types.ParameterStringFilter
+	if strings.Trim(n.path, "/") != "" {
+		filters = append(filters, types.ParameterStringFilter{
+			Key:    aws.String("Path"),
+			Option: aws.String("Recursive"),
+			Values: []string{n.path},
+		})
+	}
+	metas, err := describeParameters(filters, b.client)
+	if err != nil {
+		return err
+	}
+	for _, m := range metas {
+		b.insert(n, aws.ToString(m.Name), m.Type)
+	}
+	markLoaded(n)
+	return nil
+}
+
+func markLoaded(n *browseNode) {
+	n.loaded = true
+	for _, c := range n.children {
+		markLoaded(c)
+	}
+}
+
+// insert adds the parameter name (somewhere below under) to the tree
+func (b *browser) insert(under *browseNode, name string, paramType types.ParameterType) *browseNode {
+	rel := strings.Trim(strings.TrimPrefix(name, under.path), "/")
+	current := under
+	if rel == "" {
+		current.isParam = true
+		current.paramType = paramType
+		return current
+	}
+	for _, part := range strings.Split(rel, "/") {
+		current = current.child(part)
+	}
+	current.isParam = true
+	current.paramType = paramType
+	return current
+}
+
+// child returns the child of n called part, creating it if needed. A new child
+// of a loaded node is known to be complete, otherwise it is loaded on expand.
+func (n *browseNode) child(part string) *browseNode {
+	c, ok := n.children[part]
+	if !ok {
+		c = &browseNode{
+			name:     part,
+			path:     strings.TrimSuffix(n.path, "/") + "/" + part,
+			parent:   n,
+			children: make(map[string]*browseNode),
+			loaded:   n.loaded,
+		}
+		n.children[part] = c
+	}
+	return c
+}
+
+func (n *browseNode) sortedChildren() []*browseNode {
+	children := make([]*browseNode, 0, len(n.children))
+	for _, c := range n.children {
+		children = append(children, c)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].name < children[j].name
+	})
+	return children
+}
+
+type browseRow struct {
+	node  *browseNode
+	depth int
+}
+
+func (b *browser) visible() []browseRow {
+	var rows []browseRow
+	var walk func(n *browseNode, depth int)
+	walk = func(n *browseNode, depth int) {
+		rows = append(rows, browseRow{node: n, depth: depth})
+		if !n.expanded {
+			return
+		}
+		for _, c := range n.sortedChildren() {
+			walk(c, depth+1)
+		}
+	}
+	walk(b.root, 0)
+	return rows
+}
+
+func (b *browser) selected() *browseNode {
+	rows := b.visible()
+	if b.cursor >= len(rows) {
+		b.cursor = len(rows) - 1
+	}
+	return rows[b.cursor].node
+}
+
+func (b *browser) run() error {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	// Alternate screen, hidden cursor
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		term.Restore(fd, state)
+	}()
+
+	keys := make(chan string)
+	go readKeys(keys)
+
+	b.requestDetails()
+	for !b.quitting {
+		b.render()
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			b.handleKey(key)
+		case res := <-b.results:
+			delete(b.loading, res.path)
+			// A synchronous reveal may have landed meanwhile
+			if b.details[res.path] == nil {
+				b.details[res.path] = res.details
+			}
+		}
+	}
+	return nil
+}
+
+// readKeys turns raw stdin bytes into key names ("up", "enter", "a", ...)
+func readKeys(keys chan<- string) {
+	buf := make([]byte, 16)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		seq := string(buf[:n])
+		switch seq {
+		case "\x1b[A", "\x1bOA":
+			keys <- "up"
+		case "\x1b[B", "\x1bOB":
+			keys <- "down"
+		case "\x1b[C", "\x1bOC":
+			keys <- "right"
+		case "\x1b[D", "\x1bOD":
+			keys <- "left"
+		case "\x1b[5~":
+			keys <- "pgup"
+		case "\x1b[6~":
+			keys <- "pgdown"
+		case "\x1b[H", "\x1b[1~":
+			keys <- "home"
+		case "\x1b[F", "\x1b[4~":
+			keys <- "end"
+		case "\x1b":
+			keys <- "esc"
+		case "\r", "\n":
+			keys <- "enter"
+		case "\x7f", "\x08":
+			keys <- "backspace"
+		case "\x03":
+			keys <- "ctrl+c"
+		default:
+			if strings.HasPrefix(seq, "\x1b") {
+				continue
+			}
+			// Pasted text arrives as one chunk
+			for _, r := range seq {
+				keys <- string(r)
+			}
+		}
+	}
+}
+
+func (b *browser) handleKey(key string) {
+	if b.prompt != nil {
+		b.handlePromptKey(key)
+		return
+	}
+
+	b.status = ""
+	rows := b.visible()
+	_, height := b.size()
+	page := max(height-browseDetailsHeight-3, 1)
+
+	switch key {
+	case "q", "ctrl+c":
+		b.quitting = true
+	case "up", "k":
+		b.cursor = max(b.cursor-1, 0)
+	case "down", "j":
+		b.cursor = min(b.cursor+1, len(rows)-1)
+	case "pgup":
+		b.cursor = max(b.cursor-page, 0)
+	case "pgdown":
+		b.cursor = min(b.cursor+page, len(rows)-1)
+	case "home", "g":
+		b.cursor = 0
+	case "end", "G":
+		b.cursor = len(rows) - 1
+	case "right", "l", "enter":
+		n := b.selected()
+		if len(n.children) > 0 || !n.loaded {
+			if err := b.expand(n); err != nil {
+				b.status = style(roleError).Sprint(extractMessage(err))
+			}
+		}
+	case "left", "h":
+		n := b.selected()
+		if n.expanded && len(n.children) > 0 {
+			n.expanded = false
+		} else if n.parent != nil {
+			for i, r := range rows {
+				if r.node == n.parent {
+					b.cursor = i
+					break
+				}
+			}
+		}
+	case "r":
+		b.reveal()
+	case "e":
+		b.startEdit()
+	case "d":
+		b.startDelete()
+	case "c":
+		b.startCopy()
+	case "R":
+		b.refresh()
+	}
+
+	b.requestDetails()
+}
+
+func (b *browser) handlePromptKey(key string) {
+	p := b.prompt
+	switch {
+	case key == "esc" || key == "ctrl+c":
+		b.prompt = nil
+		b.status = "Cancelled."
+	case p.confirm:
+		b.prompt = nil
+		if key == "y" || key == "Y" {
+			p.done(b, "y")
+		} else {
+			b.status = "Cancelled."
+		}
+	case key == "enter":
+		b.prompt = nil
+		p.done(b, p.input)
+	case key == "backspace":
+		if p.input != "" {
+			_, size := utf8.DecodeLastRuneInString(p.input)
+			p.input = p.input[:len(p.input)-size]
+		}
+	case utf8.RuneCountInString(key) == 1:
+		p.input += key
+	}
+}
+
+// requestDetails fetches metadata and history for the selected parameter in the background
+func (b *browser) requestDetails() {
+	n := b.selected()
+	if !n.isParam || b.details[n.path] != nil || b.loading[n.path] {
+		return
+	}
+	b.loading[n.path] = true
+	go func(name string) {
+		b.results <- browseResult{path: name, details: b.fetchDetails(name, false)}
+	}(n.path)
+}
+
+func (b *browser) fetchDetails(name string, decrypt bool) *browseDetails {
+	d := &browseDetails{revealed: decrypt}
+
+	out, err := b.client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(decrypt),
+	})
+	if err != nil {
+		d.err = extractMessage(err)
+		return d
+	}
+	d.value = aws.ToString(out.Parameter.Value)
+
+	if meta, err := describeParametersByName([]string{name}, b.client); err == nil && len(meta) > 0 {
+		d.meta = &meta[0]
+	}
+
+	paginator := ssm.NewGetParameterHistoryPaginator(b.client, &ssm.GetParameterHistoryInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(false),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			break
+		}
+		d.history = append(d.history, page.Parameters...)
+	}
+	return d
+}
+
+func (b *browser) reveal() {
+	n := b.selected()
+	if !n.isParam {
+		return
+	}
+	if d := b.details[n.path]; d != nil && d.revealed {
+		delete(b.details, n.path)
+		return
+	}
+	b.details[n.path] = b.fetchDetails(n.path, true)
+}
+
+func (b *browser) startEdit() {
+	n := b.selected()
+	if !n.isParam {
+		b.status = "Select a parameter to edit."
+		return
+	}
+	current := ""
+	if d := b.details[n.path]; d != nil && (d.revealed || n.paramType != types.ParameterTypeSecureString) {
+		current = d.value
+	}
+	b.prompt = &browsePrompt{
+		label: fmt.Sprintf("New value for %s: ", n.path),
+		input: current,
+		done: func(b *browser, value string) {
+			if value == "" {
+				b.status = "Parameter values can't be empty."
+				return
+			}
+			b.prompt = &browsePrompt{
+				label:   fmt.Sprintf("Overwrite %s? (y/N) ", n.path),
+				confirm: true,
+				done: func(b *browser, _ string) {
+					b.applyEdit(n, value)
+				},
+			}
+		},
+	}
+}
+
+func (b *browser) applyEdit(n *browseNode, value string) {
+	file, err := backupParameters("edit", []string{n.path}, b.client)
+	if err != nil {
+		b.status = style(roleError).Sprintf("Backup failed: %v", err)
+		return
+	}
+
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(n.path),
+		Value:     aws.String(value),
+		Type:      n.paramType,
+		Overwrite: aws.Bool(true),
+	}
+	if d := b.details[n.path]; d != nil && d.meta != nil && d.meta.KeyId != nil && n.paramType == types.ParameterTypeSecureString {
+		input.KeyId = d.meta.KeyId
+	}
+	if _, err := b.client.PutParameter(ctx, input); err != nil {
+		b.status = style(roleError).Sprintf("%s Failed to update %s: %s", icon(markFail), n.path, extractMessage(err))
+		return
+	}
+	delete(b.details, n.path)
+	b.status = fmt.Sprintf("%s Updated %s", icon(markOK), n.path)
+	if file != "" {
+		b.status += style(roleMuted).Sprintf(" (backup %s)", file)
+	}
+}
+
+func (b *browser) startDelete() {
+	n := b.selected()
+	if !n.isParam {
+		b.status = "Select a parameter to delete."
+		return
+	}
+	b.prompt = &browsePrompt{
+		label:   fmt.Sprintf("Delete %s? (y/N) ", n.path),
+		confirm: true,
+		done: func(b *browser, _ string) {
+			file, err := backupParameters("delete", []string{n.path}, b.client)
+			if err != nil {
+				b.status = style(roleError).Sprintf("Backup failed: %v", err)
+				return
+			}
+			if _, err := b.client.DeleteParameter(ctx, &ssm.DeleteParameterInput{Name: aws.String(n.path)}); err != nil {
+				b.status = style(roleError).Sprintf("%s Failed to delete %s: %s", icon(markFail), n.path, extractMessage(err))
+				return
+			}
+			b.remove(n)
+			b.status = fmt.Sprintf("%s Deleted %s", icon(markOK), n.path)
+			if file != "" {
+				b.status += style(roleMuted).Sprintf(" (backup %s)", file)
+			}
+		},
+	}
+}
+
+// remove drops a deleted parameter, pruning branches left empty
+func (b *browser) remove(n *browseNode) {
+	delete(b.details, n.path)
+	n.isParam = false
+	for n != b.root && !n.isParam && len(n.children) == 0 {
+		delete(n.parent.children, n.name)
+		n = n.parent
+	}
+}
+
+func (b *browser) startCopy() {
+	n := b.selected()
+	if !n.isParam {
+		b.status = "Select a parameter to copy."
+		return
+	}
+	b.prompt = &browsePrompt{
+		label: fmt.Sprintf("Copy %s to: ", n.path),
+		input: n.path,
+		done: func(b *browser, dest string) {
+			dest = "/" + strings.Trim(dest, "/")
+			if dest == n.path {
+				b.status = "Destination must differ from the source."
+				return
+			}
+			b.prompt = &browsePrompt{
+				label:   fmt.Sprintf("Copy %s → %s? (y/N) ", n.path, dest),
+				confirm: true,
+				done: func(b *browser, _ string) {
+					params, err := collectSnapshotParams([]string{n.path}, b.client)
+					if err != nil || len(params) == 0 {
+						b.status = style(roleError).Sprintf("%s Failed to read %s", icon(markFail), n.path)
+						return
+					}
+					p := params[0]
+					p.Name = dest
+					if err := putSnapshotParam(p, nil, false, b.client); err != nil {
+						b.status = style(roleError).Sprintf("%s Failed to copy to %s: %s", icon(markFail), dest, extractMessage(err))
+						return
+					}
+					if dest == b.root.path || strings.HasPrefix(dest, strings.TrimSuffix(b.root.path, "/")+"/") {
+						b.insert(b.root, dest, n.paramType)
+					}
+					b.status = fmt.Sprintf("%s Copied %s → %s", icon(markOK), n.path, dest)
+				},
+			}
+		},
+	}
+}
+
+func (b *browser) refresh() {
+	b.root.children = make(map[string]*browseNode)
+	b.root.loaded = false
+	b.details = make(map[string]*browseDetails)
+	b.cursor = 0
+	if err := b.expand(b.root); err != nil {
+		b.status = style(roleError).Sprint(extractMessage(err))
+		return
+	}
+	b.status = "Refreshed."
+}
+
+func (b *browser) size() (int, int) {
+	w, h, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 80, 24
+	}
+	return w, h
+}
+
+func (b *browser) render() {
+	width, height := b.size()
+	rows := b.visible()
+	treeHeight := max(height-browseDetailsHeight-2, 3)
+
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+treeHeight {
+		b.offset = b.cursor - treeHeight + 1
+	}
+
+	var lines []string
+	for i := b.offset; i < min(b.offset+treeHeight, len(rows)); i++ {
+		lines = append(lines, b.renderRow(rows[i], i == b.cursor, width))
+	}
+	for len(lines) < treeHeight {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, style(roleMuted).Sprint(strings.Repeat("─", width)))
+	lines = append(lines, b.renderDetails(width)...)
+
+	var sb strings.Builder
+	sb.WriteString("\x1b[H")
+	for i, line := range lines[:min(len(lines), height-1)] {
+		if i > 0 {
+			sb.WriteString("\r\n")
+		}
+		sb.WriteString(line)
+		sb.WriteString("\x1b[K")
+	}
+	sb.WriteString("\r\n")
+	sb.WriteString(b.renderStatus(width))
+	sb.WriteString("\x1b[K\x1b[J")
+	fmt.Print(sb.String())
+}
+
+func (b *browser) renderRow(r browseRow, selected bool, width int) string {
+	n := r.node
+	marker := "  "
+	if len(n.children) > 0 || !n.loaded {
+		marker = "▸ "
+		if n.expanded {
+			marker = "▾ "
+		}
+	}
+
+	indent := strings.Repeat("  ", r.depth) + marker
+	name := truncate(n.name, width-utf8.RuneCountInString(indent)-3)
+	if selected {
+		if n.isParam && n.paramType == types.ParameterTypeSecureString {
+			name += " " + icon(markSecure)
+		}
+		return style(roleSelected).Sprint(indent + name)
+	}
+	if n.isParam {
+		return indent + styleLabel(name, n.paramType)
+	}
+	return indent + style(roleBranch).Sprint(name)
+}
+
+func (b *browser) renderDetails(width int) []string {
+	n := b.selected()
+	// Values are truncated by the caller before styling, so escape codes are never cut
+	valueWidth := max(width-14, 10)
+	field := func(k, v string) string {
+		return fmt.Sprintf("%s %s", style(roleHeading).Sprintf("%-13s", k+":"), v)
+	}
+
+	if !n.isParam {
+		return []string{
+			field("Path", truncate(n.path, valueWidth)),
+			field("Children", fmt.Sprintf("%d", len(n.children))),
+		}
+	}
+
+	d := b.details[n.path]
+	if d == nil {
+		return []string{field("Name", truncate(n.path, valueWidth)), style(roleMuted).Sprint("Loading…")}
+	}
+	if d.err != "" {
+		return []string{field("Name", truncate(n.path, valueWidth)), style(roleError).Sprint(truncate(d.err, width))}
+	}
+
+	value := truncate(strings.ReplaceAll(d.value, "\n", "⏎"), valueWidth)
+	if n.paramType == types.ParameterTypeSecureString && !d.revealed {
+		value = style(roleMuted).Sprint("•••••••• (press r to reveal)")
+	}
+	lines := []string{
+		field("Name", styleLabel(truncate(n.path, valueWidth-3), n.paramType)),
+		field("Value", value),
+	}
+	if m := d.meta; m != nil {
+		lines = append(lines,
+			field("Type", fmt.Sprintf("%s  Tier: %s  Version: %d", m.Type, m.Tier, m.Version)),
+			field("Modified", fmt.Sprintf("%s by %s", aws.ToTime(m.LastModifiedDate).Local().Format("2006-01-02 15:04:05"), aws.ToString(m.LastModifiedUser))),
+		)
+		if m.KeyId != nil {
+			lines = append(lines, field("KMS key", truncate(aws.ToString(m.KeyId), valueWidth)))
+		}
+		if m.Description != nil {
+			lines = append(lines, field("Description", truncate(strings.ReplaceAll(aws.ToString(m.Description), "\n", " "), valueWidth)))
+		}
+	}
+
+	if len(d.history) > 0 {
+		lines = append(lines, style(roleHeading).Sprint("History:"))
+		for i := len(d.history) - 1; i >= 0 && len(lines) < browseDetailsHeight; i-- {
+			h := d.history[i]
+			labels := ""
+			if len(h.Labels) > 0 {
+				labels = fmt.Sprintf(" [%s]", strings.Join(h.Labels, ", "))
+			}
+			lines = append(lines, truncate(fmt.Sprintf("  v%-4d %s  %s%s",
+				h.Version,
+				aws.ToTime(h.LastModifiedDate).Local().Format("2006-01-02 15:04:05"),
+				aws.ToString(h.LastModifiedUser),
+				labels), width))
+		}
+	}
+	return lines
+}
+
+func (b *browser) renderStatus(width int) string {
+	if b.prompt != nil {
+		return style(rolePrompt).Sprint(b.prompt.label) + b.prompt.input + "█"
+	}
+	if b.status != "" {
+		return b.status
+	}
+	help := "↑↓ move  →/enter open  ← close  r reveal  e edit  d delete  c copy  R refresh  q quit"
+	return style(roleMuted).Sprint(truncate(help, width))
+}
+
+// truncate cuts s to width runes, adding an ellipsis when it had to cut
+func truncate(s string, width int) string {
+	if width <= 1 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:width-1]) + "…"
+}
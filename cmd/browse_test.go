@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBrowseExpand(t *testing.T) {
+	fake, client := newFakeSSM(t, map[string]string{
+		"/app/dev/db/host":     "h",
+		"/app/dev/db/port":     "1",
+		"/app/dev/api/key":     "k",
+		"/app/dev/name":        "n",
+		"/app/prod/db/host":    "p",
+		"/app/development/url": "u",
+	})
+	b := newBrowser("/app/dev", client)
+
+	steps := []struct {
+		expand string
+		want   []string
+	}{
+		{"", []string{"/app/dev", "  api", "  db", "  name"}},
+		{"/app/dev/db", []string{"/app/dev", "  api", "  db", "    host", "    port", "  name"}},
+		{"/app/dev/api", []string{"/app/dev", "  api", "    key", "  db", "    host", "    port", "  name"}},
+	}
+	for _, step := range steps {
+		n := b.root
+		if step.expand != "" {
+			for _, part := range strings.Split(strings.TrimPrefix(step.expand, "/app/dev/"), "/") {
+				n = n.children[part]
+			}
+		}
+		if err := b.expand(n); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, row := range b.visible() {
+			got = append(got, strings.Repeat("  ", row.depth)+row.node.name)
+		}
+		if !reflect.DeepEqual(got, step.want) {
+			t.Errorf("after expanding %q:\ngot  %q\nwant %q", step.expand, got, step.want)
+		}
+	}
+
+	// Names are listed once, every later expand works from the cached skeleton
+	if len(fake.calls) != 1 || !strings.HasPrefix(fake.calls[0], "DescribeParameters") {
+		t.Errorf("got calls %v, want a single DescribeParameters", fake.calls)
+	}
+	if leaf := b.root.children["db"].children["host"]; !leaf.isParam || leaf.path != "/app/dev/db/host" {
+		t.Errorf("db/host: isParam %v, path %q", leaf.isParam, leaf.path)
+	}
+}
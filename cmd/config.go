@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	configFile string
+	envName    string
+	awsProfile string
+)
+
+// projectConfigNames are looked up in the working directory and each of its parents
+var projectConfigNames = []string{".aws-ssm.yaml", ".aws-ssm.yml"}
+
+// projectConfig is the content of .aws-ssm.yaml: defaults shared by every call plus
+// named environments that override them when selected with --env
+type projectConfig struct {
+	Defaults     envConfig            `yaml:"defaults"`
+	Environments map[string]envConfig `yaml:"environments"`
+	Secrets      secretsConfig        `yaml:"secrets"`
+}
+
+type envConfig struct {
+	Profile    string            `yaml:"profile"`
+	Region     string            `yaml:"region"`
+	Prefix     string            `yaml:"prefix"`
+	KMSKeyID   string            `yaml:"kms_key_id"`
+	AutoSecure *bool             `yaml:"auto_secure"`
+	Tier       string            `yaml:"tier"`
+	Tags       map[string]string `yaml:"tags"`
+}
+
+// configCommands are the commands whose flags are filled in from the selected environment
+var configCommands = map[string]bool{"load": true, "save": true, "delete": true, "tree": true}
+
+// explicitPrefixCommands destroy what --prefix points at, so they never take the
+// prefix from the config file and must be given one on the command line
+var explicitPrefixCommands = map[string]bool{"delete": true, "purge": true}
+
+// merge returns e with every setting of override applied on top
+func (e envConfig) merge(override envConfig) envConfig {
+	if override.Profile != "" {
+		e.Profile = override.Profile
+	}
+	if override.Region != "" {
+		e.Region = override.Region
+	}
+	if override.Prefix != "" {
+		e.Prefix = override.Prefix
+	}
+	if override.KMSKeyID != "" {
+		e.KMSKeyID = override.KMSKeyID
+	}
+	if override.AutoSecure != nil {
+		e.AutoSecure = override.AutoSecure
+	}
+	if override.Tier != "" {
+		e.Tier = override.Tier
+	}
+	if len(override.Tags) > 0 {
+		tags := make(map[string]string, len(e.Tags)+len(override.Tags))
+		for k, v := range e.Tags {
+			tags[k] = v
+		}
+		for k, v := range override.Tags {
+			tags[k] = v
+		}
+		e.Tags = tags
+	}
+	return e
+}
+
+// findConfigFile returns --config, the nearest .aws-ssm.yaml above the working
+// directory, or config.yaml in the user config dir, whichever comes first
+func findConfigFile() (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+
+	dir, err := os.Getwd()
+	if err == nil {
+		for {
+			for _, name := range projectConfigNames {
+				candidate := filepath.Join(dir, name)
+				if _, err := os.Stat(candidate); err == nil {
+					return candidate, nil
+				}
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+
+	if base, err := os.UserConfigDir(); err == nil {
+		candidate := filepath.Join(base, Name, "config.yaml")
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+func loadProjectConfig() (*projectConfig, string, error) {
+	file, err := findConfigFile()
+	if err != nil || file == "" {
+		return &projectConfig{}, "", err
+	}
+
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, file, fmt.Errorf("failed to read config %s: %w", file, err)
+	}
+	var cfg projectConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && err != io.EOF {
+		return nil, file, fmt.Errorf("failed to parse config %s: %w", file, err)
+	}
+	return &cfg, file, nil
+}
+
+// resolveEnv returns the defaults merged with the environment picked by --env
+func (c *projectConfig) resolveEnv(name, file string) (envConfig, error) {
+	if name == "" {
+		return c.Defaults, nil
+	}
+	env, ok := c.Environments[name]
+	if !ok {
+		if file == "" {
+			return envConfig{}, fmt.Errorf("--env %s given but no %s was found", name, projectConfigNames[0])
+		}
+		known := make([]string, 0, len(c.Environments))
+		for k := range c.Environments {
+			known = append(known, k)
+		}
+		sort.Strings(known)
+		return envConfig{}, fmt.Errorf("environment %q is not defined in %s (known: %s)", name, file, strings.Join(known, ", "))
+	}
+	return c.Defaults.merge(env), nil
+}
+
+// applyProjectConfig loads the config file and uses it for every flag of cmd that
+// was not given on the command line
+func applyProjectConfig(cmd *cobra.Command) error {
+	cfg, file, err := loadProjectConfig()
+	if err != nil {
+		return err
+	}
+	env, err := cfg.resolveEnv(envName, file)
+	if err != nil {
+		return err
+	}
+	if detector, err = newSecretDetector(cfg.Secrets); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	setDefault := func(flag, value string) error {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil || f.Changed || value == "" {
+			return nil
+		}
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid %s %q in %s: %w", flag, value, file, err)
+		}
+		return nil
+	}
+
+	if err := setDefault("profile", env.Profile); err != nil {
+		return err
+	}
+	if err := setDefault("region", env.Region); err != nil {
+		return err
+	}
+	if !configCommands[cmd.Name()] {
+		return nil
+	}
+
+	if !explicitPrefixCommands[cmd.Name()] {
+		if err := setDefault("prefix", env.Prefix); err != nil {
+			return err
+		}
+	}
+	if err := setDefault("key-id", env.KMSKeyID); err != nil {
+		return err
+	}
+	if err := setDefault("tier", env.Tier); err != nil {
+		return err
+	}
+	if cfg.Secrets.Enforce {
+		if err := setDefault("enforce", "true"); err != nil {
+			return err
+		}
+	}
+	if env.AutoSecure != nil {
+		if err := setDefault("auto-secure", fmt.Sprint(*env.AutoSecure)); err != nil {
+			return err
+		}
+	}
+	if f := cmd.Flags().Lookup("tag"); f != nil && !f.Changed {
+		for _, k := range sortedKeys(env.Tags) {
+			if err := f.Value.Set(k + "=" + env.Tags[k]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
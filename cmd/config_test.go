@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+const testProjectConfig = `
+defaults:
+  prefix: /app/dev
+  tier: standard
+  tags:
+    team: platform
+    env: dev
+environments:
+  prod:
+    prefix: /app/prod
+    kms_key_id: alias/prod
+    auto_secure: true
+    tags:
+      env: prod
+`
+
+func TestEnvConfigMerge(t *testing.T) {
+	yes, no := true, false
+	base := envConfig{Profile: "dev", Region: "eu-west-1", Prefix: "/a", AutoSecure: &yes, Tags: map[string]string{"team": "x", "env": "dev"}}
+	tests := []struct {
+		name     string
+		override envConfig
+		want     envConfig
+	}{
+		{"empty override", envConfig{}, base},
+		{"fields", envConfig{Region: "us-east-1", KMSKeyID: "k", AutoSecure: &no},
+			envConfig{Profile: "dev", Region: "us-east-1", Prefix: "/a", KMSKeyID: "k", AutoSecure: &no, Tags: base.Tags}},
+		{"tags merge", envConfig{Tags: map[string]string{"env": "prod"}},
+			envConfig{Profile: "dev", Region: "eu-west-1", Prefix: "/a", AutoSecure: &yes, Tags: map[string]string{"team": "x", "env": "prod"}}},
+	}
+	for _, tt := range tests {
+		if got := base.merge(tt.override); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if base.Tags["env"] != "dev" {
+		t.Errorf("merge changed the base tags: %v", base.Tags)
+	}
+}
+
+func TestApplyProjectConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".aws-ssm.yaml")
+	if err := os.WriteFile(file, []byte(testProjectConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer func(c, e string, d *secretDetector) { configFile, envName, detector = c, e, d }(configFile, envName, detector)
+	configFile = file
+
+	tests := []struct {
+		command string
+		env     string
+		args    []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"load", "", nil, map[string]string{"prefix": "/app/dev", "tier": "standard", "key-id": "", "auto-secure": "false", "tag": "[env=dev,team=platform]"}, false},
+		{"load", "prod", nil, map[string]string{"prefix": "/app/prod", "tier": "standard", "key-id": "alias/prod", "auto-secure": "true", "tag": "[env=prod,team=platform]"}, false},
+		{"load", "prod", []string{"--prefix", "/mine", "--tier", "advanced", "--tag", "a=b"}, map[string]string{"prefix": "/mine", "tier": "advanced", "key-id": "alias/prod", "tag": "[a=b]"}, false},
+		{"delete", "prod", nil, map[string]string{"prefix": ""}, false},
+		{"purge", "prod", nil, map[string]string{"prefix": ""}, false},
+		{"ls", "prod", nil, map[string]string{"prefix": ""}, false},
+		{"load", "staging", nil, nil, true},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{Use: tt.command}
+		cmd.Flags().String("prefix", "", "")
+		cmd.Flags().String("key-id", "", "")
+		cmd.Flags().String("tier", "", "")
+		cmd.Flags().Bool("auto-secure", false, "")
+		cmd.Flags().StringToString("tag", nil, "")
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		envName = tt.env
+
+		err := applyProjectConfig(cmd)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s --env %q: error = %v, want error %v", tt.command, tt.env, err, tt.wantErr)
+			continue
+		}
+		for flag, want := range tt.want {
+			if got := cmd.Flags().Lookup(flag).Value.String(); got != want {
+				t.Errorf("%s --env %q %v: --%s = %q, want %q", tt.command, tt.env, tt.args, flag, got, want)
+			}
+		}
+	}
+}
@@ -2,33 +2,58 @@ package cmd
 
 import (
 	"bufio"
-	"bytes"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
-	deleteFile   string
-	deletePrefix string
-	deleteYes    bool
+	deleteFile             string
+	deletePrefix           string
+	deleteYes              bool
+	deleteRecursive        bool
+	deleteInclude          []string
+	deleteExclude          []string
+	deleteConfirmThreshold int
+	deleteSoft             bool
 )
 
+// ssmBatchSize is the maximum number of names accepted by GetParameters and DeleteParameters
+const ssmBatchSize = 10
+
 var deleteCmd = &cobra.Command{
 	Use:     "delete",
-	Short:   "Delete parameters from AWS SSM based on a YAML file",
+	Short:   "Delete parameters from AWS SSM based on a YAML file or a whole prefix",
 	Aliases: []string{"d", "de"},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if deleteRecursive {
+			if deletePrefix == "" {
+				return fmt.Errorf("--prefix is required (delete never takes it from the config file)")
+			}
+			if deleteFile != "" {
+				return fmt.Errorf("--file cannot be combined with --recursive")
+			}
+			if strings.Trim(deletePrefix, "/") == "" {
+				return fmt.Errorf("refusing to recursively delete the root prefix")
+			}
+			client, err := newSSMClient()
+			if err != nil {
+				return err
+			}
+			return deleteByPrefix(deletePrefix, client)
+		}
+
 		if deleteFile == "" || deletePrefix == "" {
-			return fmt.Errorf("--file and --prefix are required")
+			return fmt.Errorf("--file and --prefix are required (or use --prefix with --recursive); delete never takes the prefix from the config file")
+		}
+		if err := validateNullFlags(); err != nil {
+			return err
 		}
 
 		rawYaml, err := os.ReadFile(deleteFile)
@@ -36,101 +61,293 @@ var deleteCmd = &cobra.Command{
 			return fmt.Errorf("error reading YAML file: %w", err)
 		}
 
-		var data map[string]interface{}
-		decoder := yaml.NewDecoder(bytes.NewReader(rawYaml))
-		if err := decoder.Decode(&data); err != nil {
-			return fmt.Errorf("error parsing YAML: %w", err)
+		flatKeys, err := fileDeleteKeys(rawYaml, deletePrefix)
+		if err != nil {
+			return err
 		}
-
-		flatKeys := flattenYAMLKeys(data, deletePrefix)
 		if len(flatKeys) == 0 {
-			fmt.Println("No parameters found in the YAML file.")
+			fmt.Fprintln(humanOut(), "No parameters found in the YAML file.")
 			return nil
 		}
 
-		//awsCfg, err := config.LoadDefaultConfig(context.TODO())
-		var cfgOpts []func(*config.LoadOptions) error
-		if awsRegion != "" {
-			cfgOpts = append(cfgOpts, config.WithRegion(awsRegion))
-		}
-		awsCfg, err := config.LoadDefaultConfig(ctx, cfgOpts...)
+		client, err := newSSMClient()
 		if err != nil {
-			return fmt.Errorf("failed to load AWS config: %w", err)
+			return err
 		}
-		client := ssm.NewFromConfig(awsCfg)
 
-		fmt.Printf("The following %d parameters will be deleted from SSM:\n", len(flatKeys))
+		lookups := lookupParameters(flatKeys, client)
 
-		typedKeys := make(map[string]types.ParameterType)
+		var toDelete, missing []string
 		for _, key := range flatKeys {
-			out, err := client.GetParameter(ctx, &ssm.GetParameterInput{
-				Name:           aws.String(key),
-				WithDecryption: aws.Bool(false),
-			})
-			if err == nil {
-				typedKeys[key] = out.Parameter.Type
+			if lookups[key].status == paramNotFound {
+				missing = append(missing, key)
+			} else {
+				toDelete = append(toDelete, key)
 			}
 		}
 
-		for _, key := range flatKeys {
-			lockIcon := ""
-			if typedKeys[key] == types.ParameterTypeSecureString {
-				lockIcon = " 🔒"
+		fmt.Fprintf(humanOut(), "The following %d parameters will be %s:\n", len(toDelete), deleteAction())
+		for _, key := range toDelete {
+			l := lookups[key]
+			if emit(plannedDelete(key, l)) {
+				continue
 			}
-			fmt.Printf("%s%s\n", color.New(color.FgHiBlack, color.Bold).Sprint(key), lockIcon)
+			suffix := ""
+			switch l.status {
+			case paramFound:
+				if l.paramType == types.ParameterTypeSecureString {
+					suffix = " " + icon(markSecure)
+				}
+			case paramAccessDenied:
+				suffix = style(roleWarning).Sprint(" (access denied, type unknown)")
+			case paramLookupFailed:
+				suffix = style(roleWarning).Sprintf(" (lookup failed: %s)", l.message)
+			}
+			fmt.Printf("%s%s\n", style(roleItem).Sprint(key), suffix)
 		}
 
-		if !deleteYes {
-			fmt.Print("Are you sure? (y/N): ")
-			reader := bufio.NewReader(os.Stdin)
-			input, _ := reader.ReadString('\n')
-			input = strings.TrimSpace(strings.ToLower(input))
-			if input != "y" && input != "yes" {
-				fmt.Println("Aborted.")
-				return nil
+		if len(missing) > 0 && jsonOutput() {
+			for _, key := range missing {
+				emit(event{Operation: "delete", Path: key, Result: resultNotFound})
+			}
+		} else if len(missing) > 0 {
+			fmt.Printf("\nThe following %d parameters do not exist and will be skipped:\n", len(missing))
+			for _, key := range missing {
+				fmt.Printf("%s\n", style(roleMuted).Sprint(key))
 			}
 		}
 
-		for _, key := range flatKeys {
-			_, err := client.DeleteParameter(ctx, &ssm.DeleteParameterInput{
-				Name: aws.String(key),
-			})
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to delete %s: %v\n", color.New(color.FgWhite, color.Bold).Sprint(key), color.New(color.FgRed).Sprint(extractMessage(err)))
-			} else {
-				fmt.Printf("✅ Deleted %s\n", key)
-			}
+		if len(toDelete) == 0 {
+			fmt.Fprintln(humanOut(), "Nothing to delete.")
+			return nil
 		}
 
-		return nil
+		if err := checkTrashable(toDelete); err != nil {
+			return err
+		}
+		// The keys come from a file the user picked, so the plain prompt is enough here
+		if !deleteYes && !confirm("Are you sure? (y/N): ", false) {
+			abort("delete")
+			return nil
+		}
+
+		return removeParameters(toDelete, client)
 	},
 }
 
 func init() {
-	deleteCmd.Flags().StringVarP(&deleteFile, "file", "f", "", "Path to YAML file (required)")
+	deleteCmd.Flags().StringVarP(&deleteFile, "file", "f", "", "Path to YAML file (required unless --recursive)")
 	deleteCmd.Flags().StringVarP(&deletePrefix, "prefix", "p", "", "SSM prefix to delete under (required)")
 	deleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Skip confirmation prompt")
+	deleteCmd.Flags().BoolVarP(&deleteRecursive, "recursive", "R", false, "Delete every parameter under --prefix (no YAML file needed)")
+	deleteCmd.Flags().StringSliceVarP(&deleteInclude, "include", "i", nil, "Only delete keys matching these globs, relative to --prefix (with --recursive)")
+	deleteCmd.Flags().StringSliceVarP(&deleteExclude, "exclude", "x", nil, "Skip keys matching these globs, relative to --prefix (with --recursive)")
+	deleteCmd.Flags().BoolVar(&noBackup, "no-backup", false, "Don't write a backup snapshot before deleting")
+	deleteCmd.Flags().BoolVarP(&deleteSoft, "soft", "S", false, "Move parameters into the trash prefix instead of deleting them")
+	deleteCmd.Flags().StringVar(&trashPrefix, "trash-prefix", defaultTrashPrefix, "SSM prefix used by --soft")
+	deleteCmd.Flags().IntVar(&deleteConfirmThreshold, "confirm-threshold", 10, "With --recursive, require typing the prefix to confirm when more than this many keys are affected")
+	addNullFlags(deleteCmd)
+}
+
+// deleteByPrefix removes every parameter below prefix that passes the include/exclude filters
+func deleteByPrefix(prefix string, client *ssm.Client) error {
+	prefix = "/" + strings.Trim(prefix, "/")
+	params, err := fetchAllParameterObjects(prefix, false, client)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(params))
+	for name := range params {
+		rel := strings.Trim(strings.TrimPrefix(name, prefix), "/")
+		if matchesFilters(rel, deleteInclude, deleteExclude) {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		fmt.Fprintf(humanOut(), "No parameters found under %s.\n", prefix)
+		return nil
+	}
+
+	fmt.Fprintf(humanOut(), "The following %d parameters will be %s:\n", len(keys), deleteAction())
+	for _, key := range keys {
+		if emit(event{Operation: "delete", Path: key, Type: string(params[key].Type), Result: resultPlanned}) {
+			continue
+		}
+		lockIcon := ""
+		if params[key].Type == types.ParameterTypeSecureString {
+			lockIcon = " " + icon(markSecure)
+		}
+		fmt.Printf("%s%s\n", style(roleItem).Sprint(key), lockIcon)
+	}
+
+	if err := checkTrashable(keys); err != nil {
+		return err
+	}
+	if !deleteYes && !confirmDelete(prefix, len(keys)) {
+		abort("delete")
+		return nil
+	}
+
+	return removeParameters(keys, client)
 }
 
-func flattenYAMLKeys(data interface{}, prefix string) []string {
-	var keys []string
-	var walk func(interface{}, string)
-	walk = func(node interface{}, path string) {
-		switch val := node.(type) {
-		case map[string]interface{}:
-			for k, v := range val {
-				newPath := fmt.Sprintf("%s/%s", path, k)
-				walk(v, newPath)
+// removeParameters moves names to the trash with --soft, otherwise backs them up and deletes them
+func removeParameters(names []string, client *ssm.Client) error {
+	if deleteSoft {
+		return trashParameters(names, client)
+	}
+	if err := takeBackup("delete", names, client); err != nil {
+		return err
+	}
+	deleteInBatches(names, client)
+	return nil
+}
+
+func deleteAction() string {
+	if deleteSoft {
+		return "moved to " + normalizedTrashPrefix()
+	}
+	return "deleted from SSM"
+}
+
+type paramLookupStatus int
+
+const (
+	paramFound paramLookupStatus = iota
+	paramNotFound
+	paramAccessDenied
+	paramLookupFailed
+)
+
+type paramLookup struct {
+	status    paramLookupStatus
+	paramType types.ParameterType
+	message   string
+}
+
+// lookupParameters fetches metadata for names with GetParameters, ssmBatchSize at a time.
+// When a batch is denied as a whole, its keys are retried one by one to tell apart
+// the ones we may not read from the ones that are simply missing.
+func lookupParameters(names []string, client *ssm.Client) map[string]paramLookup {
+	result := make(map[string]paramLookup, len(names))
+
+	lookupOne := func(key string) {
+		out, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(key),
+			WithDecryption: aws.Bool(false),
+		})
+		switch {
+		case err == nil:
+			result[key] = paramLookup{status: paramFound, paramType: out.Parameter.Type}
+		case errorCode(err) == "ParameterNotFound":
+			result[key] = paramLookup{status: paramNotFound}
+		case isAccessDenied(err):
+			result[key] = paramLookup{status: paramAccessDenied, message: extractMessage(err)}
+		default:
+			result[key] = paramLookup{status: paramLookupFailed, message: extractMessage(err)}
+		}
+	}
+
+	for start := 0; start < len(names); start += ssmBatchSize {
+		batch := names[start:min(start+ssmBatchSize, len(names))]
+		out, err := client.GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          batch,
+			WithDecryption: aws.Bool(false),
+		})
+		if err != nil {
+			if isAccessDenied(err) {
+				for _, key := range batch {
+					lookupOne(key)
+				}
+				continue
 			}
-		case []interface{}:
-			for i, v := range val {
-				newPath := fmt.Sprintf("%s/%d", path, i)
-				walk(v, newPath)
+			for _, key := range batch {
+				result[key] = paramLookup{status: paramLookupFailed, message: extractMessage(err)}
 			}
-		default:
-			keys = append(keys, path)
+			continue
+		}
+		for _, p := range out.Parameters {
+			result[*p.Name] = paramLookup{status: paramFound, paramType: p.Type}
 		}
+		for _, key := range out.InvalidParameters {
+			result[key] = paramLookup{status: paramNotFound}
+		}
+	}
+
+	return result
+}
+
+// plannedDelete describes a key about to be deleted for --output json
+func plannedDelete(key string, l paramLookup) event {
+	e := event{Operation: "delete", Path: key, Type: string(l.paramType), Result: resultPlanned}
+	switch l.status {
+	case paramAccessDenied:
+		e.ErrorCode, e.Message = "AccessDeniedException", l.message
+	case paramLookupFailed:
+		e.Message = l.message
+	}
+	return e
+}
+
+// confirmDelete asks for a y/N answer, or for the prefix itself when more than
+// --confirm-threshold keys are affected
+func confirmDelete(prefix string, count int) bool {
+	if count > deleteConfirmThreshold {
+		fmt.Fprintf(humanOut(), "This will delete %d parameters. Type the prefix %s to confirm: ", count, style(roleHeading).Sprint(prefix))
+		input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		return strings.TrimSpace(input) == prefix
+	}
+	return confirm("Are you sure? (y/N): ", false)
+}
+
+// deleteInBatches removes names via DeleteParameters, ssmBatchSize at a time.
+// Keys reported as InvalidParameters (already gone) are listed apart from real failures.
+func deleteInBatches(names []string, client *ssm.Client) {
+	var deleted, missing, failed int
+	for start := 0; start < len(names); start += ssmBatchSize {
+		batch := names[start:min(start+ssmBatchSize, len(names))]
+		out, err := client.DeleteParameters(ctx, &ssm.DeleteParametersInput{
+			Names: batch,
+		})
+		if err != nil {
+			for _, key := range batch {
+				emitError("delete", "delete", key, err)
+			}
+			failed += len(batch)
+			continue
+		}
+		for _, key := range out.DeletedParameters {
+			if !emit(event{Operation: "delete", Path: key, Result: resultOK}) {
+				fmt.Printf("%s Deleted %s\n", icon(markOK), key)
+			}
+		}
+		deleted += len(out.DeletedParameters)
+		for _, key := range out.InvalidParameters {
+			if !emit(event{Operation: "delete", Path: key, Result: resultNotFound}) {
+				fmt.Fprintf(os.Stderr, "%s Not found %s\n", icon(markWarn), style(roleName).Sprint(key))
+			}
+		}
+		missing += len(out.InvalidParameters)
+	}
+
+	fmt.Fprintf(humanOut(), "\nDeleted %d, not found %d, failed %d\n", deleted, missing, failed)
+}
+
+// fileDeleteKeys lists the parameters load would write for a YAML file below
+// prefix: merge keys resolved, the x-aws-ssm block left out and null leaves
+// handled by --on-null, so delete removes exactly what load created
+func fileDeleteKeys(raw []byte, prefix string) ([]string, error) {
+	values, _, err := yamlLeafValues(raw, "/"+strings.Trim(prefix, "/"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
 	}
-	walk(data, strings.TrimSuffix(prefix, "/"))
-	return keys
+	sort.Strings(keys)
+	return keys, nil
 }
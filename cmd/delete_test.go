@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileDeleteKeys(t *testing.T) {
+	defer func(mode string) { onNull = mode }(onNull)
+	doc := `
+x-aws-ssm:
+  policies:
+    - glob: "*"
+      no_change_notification: 30d
+base: &base
+  host: db.internal
+  port: 5432
+db:
+  <<: *base
+  port: 6543
+  user: app
+optional: ~
+`
+	tests := []struct {
+		onNull    string
+		want      []string
+		wantError bool
+	}{
+		{onNullSkip, []string{"/app/base/host", "/app/base/port", "/app/db/host", "/app/db/port", "/app/db/user"}, false},
+		{onNullPlaceholder, []string{"/app/base/host", "/app/base/port", "/app/db/host", "/app/db/port", "/app/db/user", "/app/optional"}, false},
+		{onNullError, nil, true},
+	}
+	for _, tt := range tests {
+		onNull = tt.onNull
+		got, err := fileDeleteKeys([]byte(doc), "app/")
+		if (err != nil) != tt.wantError {
+			t.Errorf("--on-null %s: error = %v, want error %v", tt.onNull, err, tt.wantError)
+			continue
+		}
+		if !tt.wantError && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("--on-null %s: got %v, want %v", tt.onNull, got, tt.want)
+		}
+	}
+}
+
+func TestLookupParameters(t *testing.T) {
+	stored := make(map[string]string)
+	var names []string
+	for i := 0; i < 23; i++ {
+		name := fmt.Sprintf("/app/key%02d", i)
+		names = append(names, name)
+		if i%5 != 0 {
+			stored[name] = "v"
+		}
+	}
+	fake, client := newFakeSSM(t, stored)
+	fake.denied["/app/key12"] = true
+
+	got := lookupParameters(names, client)
+
+	var batches, singles int
+	for _, call := range fake.calls {
+		switch {
+		case strings.HasPrefix(call, "GetParameters "):
+			batches++
+			if n := len(strings.Split(strings.TrimPrefix(call, "GetParameters "), ",")); n > ssmBatchSize {
+				t.Errorf("batch of %d names, want at most %d", n, ssmBatchSize)
+			}
+		case strings.HasPrefix(call, "GetParameter "):
+			singles++
+		}
+	}
+	// The denied batch is retried key by key
+	if batches != 3 || singles != ssmBatchSize {
+		t.Errorf("got %d batch and %d single lookups, want 3 and %d", batches, singles, ssmBatchSize)
+	}
+
+	for i, name := range names {
+		want := paramFound
+		switch {
+		case name == "/app/key12":
+			want = paramAccessDenied
+		case i%5 == 0:
+			want = paramNotFound
+		}
+		if got[name].status != want {
+			t.Errorf("%s: status %d, want %d", name, got[name].status, want)
+		}
+	}
+}
+
+func TestDeleteInBatches(t *testing.T) {
+	stored := make(map[string]string)
+	var names []string
+	for i := 0; i < 25; i++ {
+		name := fmt.Sprintf("/app/key%02d", i)
+		names = append(names, name)
+		stored[name] = "v"
+	}
+	fake, client := newFakeSSM(t, stored)
+	names = append(names, "/app/gone")
+
+	deleteInBatches(names, client)
+
+	sizes := []int{10, 10, 6}
+	if len(fake.calls) != len(sizes) {
+		t.Fatalf("got calls %v, want %d DeleteParameters batches", fake.calls, len(sizes))
+	}
+	for i, size := range sizes {
+		op, list, _ := strings.Cut(fake.calls[i], " ")
+		if n := len(strings.Split(list, ",")); op != "DeleteParameters" || n != size {
+			t.Errorf("call %d: %s with %d names, want DeleteParameters with %d", i, op, n, size)
+		}
+	}
+	if len(fake.params) != 0 {
+		t.Errorf("left behind: %v", fake.sortedNames())
+	}
+}
@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// defaultSecretKeywords are matched against whole words of each path segment, so
+// "db_password" and "apiKey" are secrets while "monkey" and "keyboard_layout" are not
+var defaultSecretKeywords = []string{"password", "secret", "token", "key", "apikey", "auth", "private"}
+
+// secretsConfig is the "secrets" section of .aws-ssm.yaml
+type secretsConfig struct {
+	// Keywords replace the default keyword list, ExtraKeywords are added to it
+	Keywords      []string `yaml:"keywords"`
+	ExtraKeywords []string `yaml:"extra_keywords"`
+	// Include and Exclude are regular expressions matched against the full path
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	// Paths are glob rules (see globMatch) on the full path, first match wins; use
+	// "**" to cover a subtree
+	Paths []secretPathRule `yaml:"paths"`
+	// Overrides pin single keys, matched as a path suffix
+	Overrides map[string]bool `yaml:"overrides"`
+	// Values configures the value scanner
+	Values valueScanConfig `yaml:"values"`
+	// Enforce makes load refuse secret-looking leaves that would be plain Strings, like --enforce
+	Enforce bool `yaml:"enforce"`
+}
+
+type valueScanConfig struct {
+	// Enabled turns the scanner on by default, like --scan-values
+	Enabled bool `yaml:"enabled"`
+	// Entropy is the Shannon entropy (bits per character) above which a token counts as random
+	Entropy float64 `yaml:"entropy"`
+	// HexEntropy is the threshold for tokens made of hex digits only, which can't go above 4
+	HexEntropy float64 `yaml:"hex_entropy"`
+	// MinLength is the shortest token the entropy check looks at
+	MinLength int `yaml:"min_length"`
+	// Patterns are extra regular expressions for known token formats
+	Patterns []string `yaml:"patterns"`
+}
+
+const (
+	defaultEntropyThreshold    = 4.5
+	defaultHexEntropyThreshold = 3.5
+	defaultEntropyMinLength    = 20
+	// hexMinLength is the shortest hex token checked, shorter ones are usually IDs
+	hexMinLength = 32
+)
+
+type valuePattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// builtinValuePatterns recognise well-known credential formats
+var builtinValuePatterns = []valuePattern{
+	{"AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[A-Z0-9]{16}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z0-9]+ )*PRIVATE KEY( BLOCK)?-----`)},
+	{"JWT", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]+`)},
+	{"credentials in URL", regexp.MustCompile(`\b[A-Za-z][A-Za-z0-9+.-]*://[^/\s:@]*:[^/\s@]+@`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+}
+
+// entropyToken finds the base64 / hex like runs the entropy check applies to
+var entropyToken = regexp.MustCompile(`[A-Za-z0-9+/=_-]+`)
+
+var hexToken = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+// awsResourceID matches resource IDs like ami-0abcdef1234567890 or
+// subnet-0a1b2c3d, which look random but are not secrets
+var awsResourceID = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z]+)*-[0-9a-f]{8,17}$`)
+
+type secretPathRule struct {
+	Glob   string `yaml:"glob"`
+	Secure bool   `yaml:"secure"`
+}
+
+// secretDetector decides whether a parameter path looks like it holds a secret.
+// Rules are checked from the most to the least specific: overrides, path rules,
+// exclude regexes, include regexes, then keywords.
+type secretDetector struct {
+	keywords  map[string]bool
+	include   []*regexp.Regexp
+	exclude   []*regexp.Regexp
+	paths     []secretPathRule
+	overrides map[string]bool
+
+	scanValues bool
+	entropy    float64
+	hexEntropy float64
+	minLength  int
+	patterns   []valuePattern
+}
+
+// detector is the detector used by every command, configured from .aws-ssm.yaml
+var detector = mustSecretDetector(secretsConfig{})
+
+func newSecretDetector(cfg secretsConfig) (*secretDetector, error) {
+	d := &secretDetector{
+		keywords:  make(map[string]bool),
+		paths:     cfg.Paths,
+		overrides: make(map[string]bool, len(cfg.Overrides)),
+	}
+
+	keywords := cfg.Keywords
+	if len(keywords) == 0 {
+		keywords = defaultSecretKeywords
+	}
+	for _, k := range append(keywords, cfg.ExtraKeywords...) {
+		d.keywords[strings.ToLower(k)] = true
+	}
+
+	compile := func(patterns []string, kind string) ([]*regexp.Regexp, error) {
+		var res []*regexp.Regexp
+		for _, p := range patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid secrets %s pattern %q: %w", kind, p, err)
+			}
+			res = append(res, re)
+		}
+		return res, nil
+	}
+	var err error
+	if d.include, err = compile(cfg.Include, "include"); err != nil {
+		return nil, err
+	}
+	if d.exclude, err = compile(cfg.Exclude, "exclude"); err != nil {
+		return nil, err
+	}
+
+	for _, r := range cfg.Paths {
+		if _, err := globRegexp(strings.Trim(r.Glob, "/")); err != nil {
+			return nil, fmt.Errorf("invalid secrets path glob %q: %w", r.Glob, err)
+		}
+	}
+	for k, v := range cfg.Overrides {
+		d.overrides["/"+strings.Trim(k, "/")] = v
+	}
+
+	d.scanValues = cfg.Values.Enabled
+	d.entropy = cfg.Values.Entropy
+	if d.entropy == 0 {
+		d.entropy = defaultEntropyThreshold
+	}
+	d.hexEntropy = cfg.Values.HexEntropy
+	if d.hexEntropy == 0 {
+		d.hexEntropy = defaultHexEntropyThreshold
+	}
+	d.minLength = cfg.Values.MinLength
+	if d.minLength == 0 {
+		d.minLength = defaultEntropyMinLength
+	}
+	d.patterns = append([]valuePattern(nil), builtinValuePatterns...)
+	for _, p := range cfg.Values.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secrets value pattern %q: %w", p, err)
+		}
+		d.patterns = append(d.patterns, valuePattern{name: "pattern " + p, re: re})
+	}
+	return d, nil
+}
+
+func mustSecretDetector(cfg secretsConfig) *secretDetector {
+	d, err := newSecretDetector(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// sensitive reports whether path should be stored as a SecureString
+func (d *secretDetector) sensitive(path string) bool {
+	secret, _ := d.classify(path)
+	return secret
+}
+
+// classify is like sensitive but also says which rule decided, for reports
+func (d *secretDetector) classify(path string) (bool, string) {
+	if secure, reason, ok := d.pinned(path); ok {
+		return secure, reason
+	}
+	return d.classifyKey(path)
+}
+
+// classifyLeaf applies the key rules when byKey is set and the value scanner when
+// byValue is set. Overrides and path rules decide before either of them.
+func (d *secretDetector) classifyLeaf(path, value string, byKey, byValue bool) (bool, string) {
+	if secure, reason, ok := d.pinned(path); ok {
+		return secure, reason
+	}
+	if byKey {
+		if secure, reason := d.classifyKey(path); secure {
+			return true, reason
+		}
+	}
+	if byValue {
+		return d.classifyValue(value)
+	}
+	return false, ""
+}
+
+// valueScanEnabled reports whether the value scanner is on, via --scan-values or config
+func valueScanEnabled() bool {
+	return scanValues || detector.scanValues
+}
+
+// pinned applies the rules that explicitly decide a path: overrides, then path rules
+func (d *secretDetector) pinned(path string) (bool, string, bool) {
+	name := "/" + strings.Trim(path, "/")
+
+	// The longest matching suffix wins, so "db/user" beats "user"
+	for i := 0; i < len(name); i++ {
+		if name[i] != '/' {
+			continue
+		}
+		if secure, ok := d.overrides[name[i:]]; ok {
+			return secure, "override " + name[i+1:], true
+		}
+	}
+	for _, r := range d.paths {
+		if globMatch(r.Glob, name) {
+			return r.Secure, "path rule " + r.Glob, true
+		}
+	}
+	return false, "", false
+}
+
+// classifyKey applies the exclude and include regexes and the keyword list
+func (d *secretDetector) classifyKey(path string) (bool, string) {
+	name := "/" + strings.Trim(path, "/")
+
+	for _, re := range d.exclude {
+		if re.MatchString(name) {
+			return false, "exclude " + re.String()
+		}
+	}
+	for _, re := range d.include {
+		if re.MatchString(name) {
+			return true, "include " + re.String()
+		}
+	}
+	// Parent segments count too, so everything under a "secrets" branch is a secret
+	for _, segment := range strings.Split(strings.Trim(name, "/"), "/") {
+		for _, word := range splitWords(segment) {
+			if d.keywords[word] || d.keywords[strings.TrimSuffix(word, "s")] {
+				return true, "keyword " + word
+			}
+		}
+	}
+	return false, ""
+}
+
+// classifyValue looks for known credential formats and high entropy tokens in value
+func (d *secretDetector) classifyValue(value string) (bool, string) {
+	for _, p := range d.patterns {
+		if p.re.MatchString(value) {
+			return true, p.name
+		}
+	}
+	for _, token := range entropyToken.FindAllString(value, -1) {
+		if len(token) < d.minLength || awsResourceID.MatchString(token) {
+			continue
+		}
+		// Hex digits carry at most 4 bits per character, so they get their own threshold
+		threshold := d.entropy
+		if hexToken.MatchString(token) {
+			if len(token) < hexMinLength {
+				continue
+			}
+			threshold = d.hexEntropy
+		}
+		if e := shannonEntropy(token); e >= threshold {
+			return true, fmt.Sprintf("high entropy (%.1f bits/char)", e)
+		}
+	}
+	return false, ""
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	var e float64
+	for _, c := range counts {
+		p := float64(c) / float64(total)
+		e -= p * math.Log2(p)
+	}
+	return e
+}
+
+// splitWords breaks a key into lower-case words on separators and camelCase
+// boundaries: "dbPassword_v2" gives db, password, v2
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && len(current) > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// "apiKey" splits before K, "APIKey" splits before K too
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
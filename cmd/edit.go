@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	editPrefix string
+	editYes    bool
+)
+
+var editCmd = &cobra.Command{
+	Use:     "edit",
+	Short:   "Edit a prefix as YAML in $EDITOR and apply the changes",
+	Aliases: []string{"e", "ed"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if editPrefix == "" {
+			return fmt.Errorf("--prefix is required")
+		}
+		if err := validateNullFlags(); err != nil {
+			return err
+		}
+		prefix := "/" + strings.Trim(editPrefix, "/")
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		params, err := fetchAllParameterObjects(prefix, true, client)
+		if err != nil {
+			return err
+		}
+		values := make(map[string]string, len(params))
+		for name, p := range params {
+			values[name] = p.Value
+		}
+
+		// The temp file holds decrypted secrets. It lives in a private 0700 directory
+		// that is shredded as a whole on exit, interrupts included, so swap and
+		// backup files the editor leaves next to it go too.
+		dir, err := os.MkdirTemp("", "aws-ssm-edit-*")
+		if err != nil {
+			return fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		defer shredDir(dir)
+		editing := &atomic.Bool{}
+		defer cleanupOnSignal(dir, editing)()
+
+		tmp, err := os.OpenFile(filepath.Join(dir, "parameters.yaml"), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create temp file: %w", err)
+		}
+
+		var original map[string]interface{}
+		if len(values) > 0 {
+			original = flattenToNestedMap(values, prefix)
+		}
+		var rendered bytes.Buffer
+		enc := yaml.NewEncoder(&rendered)
+		enc.SetIndent(2)
+		err = enc.Encode(original)
+		if err == nil {
+			_, err = tmp.Write(rendered.Bytes())
+		}
+		tmp.Close()
+		if err != nil {
+			return fmt.Errorf("failed to write temp file: %w", err)
+		}
+
+		// Both sides are read back the way load reads a file, so only real edits differ
+		before, _, err := yamlLeafValues(rendered.Bytes(), prefix)
+		if err != nil {
+			return err
+		}
+
+		var after map[string]string
+		for {
+			editing.Store(true)
+			err := runEditor(tmp.Name())
+			editing.Store(false)
+			if err != nil {
+				return err
+			}
+			after, err = readEditedYAML(tmp.Name(), prefix, before)
+			if err == nil {
+				break
+			}
+			fmt.Fprintf(os.Stderr, "%s %v\n", icon(markFail), err)
+			if !confirm("Re-open the editor? (Y/n): ", true) {
+				abort("edit")
+				return nil
+			}
+		}
+
+		plan := diffValues(before, after)
+		if plan.empty() {
+			fmt.Fprintln(humanOut(), "No changes.")
+			return nil
+		}
+
+		plan.print(params)
+		if !editYes && !confirm("Apply these changes? (y/N): ", false) {
+			abort("edit")
+			return nil
+		}
+
+		return plan.apply(params, client)
+	},
+}
+
+func init() {
+	editCmd.Flags().StringVarP(&editPrefix, "prefix", "p", "", "SSM path prefix to edit (e.g. /myapp/dev) (required)")
+	editCmd.Flags().BoolVarP(&editYes, "yes", "y", false, "Apply changes without asking for confirmation")
+	editCmd.Flags().BoolVarP(&secure, "secure", "s", false, "Create new parameters as SecureString")
+	editCmd.Flags().BoolVarP(&autoSecure, "auto-secure", "a", false, "Create new secret-like keys as SecureString")
+	editCmd.Flags().BoolVar(&noBackup, "no-backup", false, "Don't write a backup snapshot before applying changes")
+	addNullFlags(editCmd)
+}
+
+// runEditor opens file in $VISUAL / $EDITOR (falling back to vi)
+func runEditor(file string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], file)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+// readEditedYAML reads the edited file like load does. Null and empty values that
+// --on-null skip leaves out keep their value from before rather than being deleted.
+func readEditedYAML(file, prefix string, before map[string]string) (map[string]string, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited file: %w", err)
+	}
+	values, skipped, err := yamlLeafValues(raw, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range skipped {
+		if old, ok := before[path]; ok {
+			values[path] = old
+		}
+	}
+	return values, nil
+}
+
+// cleanupOnSignal shreds dir and exits when the process is interrupted or
+// terminated. While editing is set, Ctrl-C belongs to the editor and is ignored
+// here. The returned function stops the handler.
+func cleanupOnSignal(dir string, editing *atomic.Bool) func() {
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				if sig == os.Interrupt && editing.Load() {
+					continue
+				}
+				shredDir(dir)
+				fmt.Fprintln(os.Stderr)
+				abort("edit")
+				os.Exit(130)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigs)
+		close(done)
+	}
+}
+
+// shredDir shreds every file below dir and removes it
+func shredDir(dir string) {
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			shredFile(path)
+		}
+		return nil
+	})
+	os.RemoveAll(dir)
+}
+
+// shredFile overwrites file with random bytes before removing it
+func shredFile(file string) {
+	if f, err := os.OpenFile(file, os.O_WRONLY, 0); err == nil {
+		if info, err := f.Stat(); err == nil {
+			io.CopyN(f, rand.Reader, info.Size())
+			f.Sync()
+		}
+		f.Close()
+	}
+	os.Remove(file)
+}
+
+type editPlan struct {
+	creates map[string]string
+	updates map[string]string
+	deletes []string
+}
+
+func diffValues(before, after map[string]string) *editPlan {
+	plan := &editPlan{
+		creates: make(map[string]string),
+		updates: make(map[string]string),
+	}
+	for k, v := range after {
+		old, ok := before[k]
+		switch {
+		case !ok:
+			plan.creates[k] = v
+		case old != v:
+			plan.updates[k] = v
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			plan.deletes = append(plan.deletes, k)
+		}
+	}
+	sort.Strings(plan.deletes)
+	return plan
+}
+
+func (p *editPlan) empty() bool {
+	return len(p.creates) == 0 && len(p.updates) == 0 && len(p.deletes) == 0
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newParamType picks the type for a key that doesn't exist yet
+func newParamType(path string) types.ParameterType {
+	if secure || (autoSecure && detector.sensitive(path)) {
+		return types.ParameterTypeSecureString
+	}
+	return types.ParameterTypeString
+}
+
+func (p *editPlan) print(existing map[string]treeParam) {
+	lock := func(t types.ParameterType) string {
+		if t == types.ParameterTypeSecureString {
+			return " " + icon(markSecure)
+		}
+		return ""
+	}
+
+	if jsonOutput() {
+		for _, k := range sortedKeys(p.creates) {
+			emit(event{Operation: "edit", Path: k, Type: string(newParamType(k)), Result: resultPlanned, Data: map[string]string{"change": "create"}})
+		}
+		for _, k := range sortedKeys(p.updates) {
+			emit(event{Operation: "edit", Path: k, Type: string(existing[k].Type), Result: resultPlanned, Data: map[string]string{"change": "update"}})
+		}
+		for _, k := range p.deletes {
+			emit(event{Operation: "edit", Path: k, Type: string(existing[k].Type), Result: resultPlanned, Data: map[string]string{"change": "delete"}})
+		}
+		return
+	}
+
+	fmt.Println("Planned changes:")
+	for _, k := range sortedKeys(p.creates) {
+		fmt.Println(style(roleSuccess).Sprintf("  + %s%s", k, lock(newParamType(k))))
+	}
+	for _, k := range sortedKeys(p.updates) {
+		fmt.Println(style(roleWarning).Sprintf("  ~ %s%s", k, lock(existing[k].Type)))
+	}
+	for _, k := range p.deletes {
+		fmt.Println(style(roleError).Sprintf("  - %s%s", k, lock(existing[k].Type)))
+	}
+	fmt.Printf("%d to create, %d to update, %d to delete\n", len(p.creates), len(p.updates), len(p.deletes))
+}
+
+func (p *editPlan) apply(existing map[string]treeParam, client *ssm.Client) error {
+	replaced := append(sortedKeys(p.updates), p.deletes...)
+	if err := takeBackup("edit", replaced, client); err != nil {
+		return err
+	}
+
+	// Keep the KMS key of SecureStrings that get a new value
+	keyIDs := make(map[string]string)
+	var secureUpdates []string
+	for k := range p.updates {
+		if existing[k].Type == types.ParameterTypeSecureString {
+			secureUpdates = append(secureUpdates, k)
+		}
+	}
+	if len(secureUpdates) > 0 {
+		meta, err := describeParametersByName(secureUpdates, client)
+		if err != nil {
+			return err
+		}
+		for _, m := range meta {
+			keyIDs[aws.ToString(m.Name)] = aws.ToString(m.KeyId)
+		}
+	}
+
+	put := func(name, value string, paramType types.ParameterType, overwrite bool) {
+		input := &ssm.PutParameterInput{
+			Name:      aws.String(name),
+			Value:     aws.String(value),
+			Type:      paramType,
+			Overwrite: aws.Bool(overwrite),
+		}
+		if keyIDs[name] != "" {
+			input.KeyId = aws.String(keyIDs[name])
+		}
+		if _, err := client.PutParameter(ctx, input); err != nil {
+			emitError("edit", "upload", name, err)
+			return
+		}
+		if !emit(event{Operation: "edit", Path: name, Type: string(paramType), Result: resultOK}) {
+			fmt.Printf("%s Uploaded %s\n", icon(markOK), name)
+		}
+	}
+
+	for _, k := range sortedKeys(p.creates) {
+		put(k, p.creates[k], newParamType(k), false)
+	}
+	for _, k := range sortedKeys(p.updates) {
+		put(k, p.updates[k], existing[k].Type, true)
+	}
+	if len(p.deletes) > 0 {
+		deleteInBatches(p.deletes, client)
+	}
+	return nil
+}
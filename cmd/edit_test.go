@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiffValues(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after map[string]string
+		creates       map[string]string
+		updates       map[string]string
+		deletes       []string
+	}{
+		{"no change", map[string]string{"/a": "1"}, map[string]string{"/a": "1"}, map[string]string{}, map[string]string{}, nil},
+		{"create", map[string]string{}, map[string]string{"/a": "1"}, map[string]string{"/a": "1"}, map[string]string{}, nil},
+		{"update", map[string]string{"/a": "1"}, map[string]string{"/a": "2"}, map[string]string{}, map[string]string{"/a": "2"}, nil},
+		{"whitespace is a change", map[string]string{"/a": "1"}, map[string]string{"/a": "1\n"}, map[string]string{}, map[string]string{"/a": "1\n"}, nil},
+		{"delete sorted", map[string]string{"/b": "1", "/a": "1", "/c": "1"}, map[string]string{"/c": "1"}, map[string]string{}, map[string]string{}, []string{"/a", "/b"}},
+		{"rename", map[string]string{"/old": "x"}, map[string]string{"/new": "x"}, map[string]string{"/new": "x"}, map[string]string{}, []string{"/old"}},
+	}
+	for _, tt := range tests {
+		plan := diffValues(tt.before, tt.after)
+		if !reflect.DeepEqual(plan.creates, tt.creates) || !reflect.DeepEqual(plan.updates, tt.updates) || !reflect.DeepEqual(plan.deletes, tt.deletes) {
+			t.Errorf("%s: got +%v ~%v -%v, want +%v ~%v -%v", tt.name, plan.creates, plan.updates, plan.deletes, tt.creates, tt.updates, tt.deletes)
+		}
+		if plan.empty() != (tt.name == "no change") {
+			t.Errorf("%s: empty() = %v", tt.name, plan.empty())
+		}
+	}
+}
+
+func TestShredDir(t *testing.T) {
+	dir := t.TempDir()
+	edit := filepath.Join(dir, "edit")
+	files := []string{"parameters.yaml", ".parameters.yaml.swp", "parameters.yaml~", "sub/4913"}
+	for _, f := range files {
+		path := filepath.Join(edit, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("password: hunter2\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shredDir(edit)
+	if _, err := os.Stat(edit); !os.IsNotExist(err) {
+		t.Errorf("%s still exists (err = %v)", edit, err)
+	}
+}
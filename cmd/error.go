@@ -18,3 +18,17 @@ func extractMessage(err error) string {
 	}
 	return err.Error() // fallback
 }
+
+// errorCode returns the AWS API error code (e.g. "ParameterNotFound") or "" for non-API errors
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+func isAccessDenied(err error) bool {
+	code := errorCode(err)
+	return code == "AccessDeniedException" || code == "AccessDenied"
+}
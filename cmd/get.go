@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	getDecrypt bool
+	getVersion int64
+	getLabel   string
+	getFormat  string
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a single parameter",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if getVersion != 0 && getLabel != "" {
+			return fmt.Errorf("--version and --label are mutually exclusive")
+		}
+		switch getFormat {
+		case "text", "raw", "json":
+		default:
+			return fmt.Errorf("unsupported --format %q (use text, raw or json)", getFormat)
+		}
+
+		// Versions and labels are addressed with a name:selector suffix
+		name := args[0]
+		if getVersion != 0 {
+			name = fmt.Sprintf("%s:%d", name, getVersion)
+		} else if getLabel != "" {
+			name = fmt.Sprintf("%s:%s", name, getLabel)
+		}
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		out, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(name),
+			WithDecryption: aws.Bool(getDecrypt),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %s", args[0], extractMessage(err))
+		}
+
+		return printParameter(out.Parameter)
+	},
+}
+
+func init() {
+	getCmd.Flags().BoolVarP(&getDecrypt, "decrypt", "d", false, "Decrypt SecureString values (requires IAM permission)")
+	getCmd.Flags().Int64VarP(&getVersion, "version", "V", 0, "Fetch a specific parameter version")
+	getCmd.Flags().StringVarP(&getLabel, "label", "l", "", "Fetch the version carrying this label")
+	getCmd.Flags().StringVarP(&getFormat, "format", "F", "text", "Output format: text, raw (value only) or json")
+}
+
+type parameterJSON struct {
+	Name             string     `json:"name"`
+	Type             string     `json:"type"`
+	Value            string     `json:"value"`
+	Version          int64      `json:"version"`
+	Selector         string     `json:"selector,omitempty"`
+	DataType         string     `json:"data_type,omitempty"`
+	ARN              string     `json:"arn,omitempty"`
+	LastModifiedDate *time.Time `json:"last_modified_date,omitempty"`
+}
+
+func printParameter(p *types.Parameter) error {
+	value := aws.ToString(p.Value)
+	details := parameterJSON{
+		Name:             aws.ToString(p.Name),
+		Type:             string(p.Type),
+		Value:            value,
+		Version:          p.Version,
+		Selector:         aws.ToString(p.Selector),
+		DataType:         aws.ToString(p.DataType),
+		ARN:              aws.ToString(p.ARN),
+		LastModifiedDate: p.LastModifiedDate,
+	}
+	if emit(event{Operation: "get", Path: details.Name, Type: details.Type, Result: resultOK, Data: details}) {
+		return nil
+	}
+
+	switch getFormat {
+	case "raw":
+		fmt.Println(value)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(details)
+	default:
+		label := style(roleString).Sprint(aws.ToString(p.Name))
+		if p.Type == types.ParameterTypeSecureString {
+			label = style(roleSecure).Sprintf("%s %s", aws.ToString(p.Name), icon(markSecure))
+		}
+		fmt.Printf("%s = %s %s\n", label, value, style(roleMuted).Sprintf("(v%d)", p.Version))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// captureStdout returns what fn writes to os.Stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestPrintParameter(t *testing.T) {
+	modified := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := &types.Parameter{
+		Name:             aws.String("/app/db/url"),
+		Type:             types.ParameterTypeSecureString,
+		Value:            aws.String("postgres://db\nline2"),
+		Version:          3,
+		LastModifiedDate: &modified,
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"raw", "postgres://db\nline2\n"},
+		{"json", `{
+  "name": "/app/db/url",
+  "type": "SecureString",
+  "value": "postgres://db\nline2",
+  "version": 3,
+  "last_modified_date": "2025-01-02T03:04:05Z"
+}
+`},
+	}
+	defer func(f string) { getFormat = f }(getFormat)
+	for _, tt := range tests {
+		getFormat = tt.format
+		var err error
+		got := captureStdout(t, func() { err = printParameter(p) })
+		if err != nil {
+			t.Errorf("%s: %v", tt.format, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestParseParameterFlags(t *testing.T) {
+	typeTests := []struct {
+		in      string
+		want    types.ParameterType
+		wantErr bool
+	}{
+		{"String", types.ParameterTypeString, false},
+		{"securestring", types.ParameterTypeSecureString, false},
+		{"STRINGLIST", types.ParameterTypeStringList, false},
+		{"secret", "", true},
+	}
+	for _, tt := range typeTests {
+		got, err := parseParameterType(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseParameterType(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+
+	tierTests := []struct {
+		in      string
+		want    types.ParameterTier
+		wantErr bool
+	}{
+		{"", "", false},
+		{"Standard", types.ParameterTierStandard, false},
+		{"advanced", types.ParameterTierAdvanced, false},
+		{"intelligent", types.ParameterTierIntelligentTiering, false},
+		{"intelligent-tiering", types.ParameterTierIntelligentTiering, false},
+		{"premium", "", true},
+	}
+	for _, tt := range tierTests {
+		got, err := parseParameterTier(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseParameterTier(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestReadSetValue(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{"argument", []string{"/p", "value"}, "", "value", false},
+		{"argument keeps newline", []string{"/p", "a\n"}, "", "a\n", false},
+		{"file drops one newline", []string{"/p"}, write("one", "secret\n\n"), "secret\n", false},
+		{"file drops CRLF", []string{"/p"}, write("crlf", "secret\r\n"), "secret", false},
+		{"both", []string{"/p", "value"}, write("both", "x"), "", true},
+		{"empty file", []string{"/p"}, write("empty", "\n"), "", true},
+		{"missing file", []string{"/p"}, filepath.Join(dir, "missing"), "", true},
+	}
+	defer func(f string) { setValueFile = f }(setValueFile)
+	for _, tt := range tests {
+		setValueFile = tt.file
+		got, err := readSetValue(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+// globMatch reports whether name matches a shell-style glob. '*' and '?' stay
+// within one path segment, '**' crosses segments. Patterns without a '/' are
+// also tried against the last segment of name, so "*password*" works at any depth.
+// The match is anchored: "db" matches a key named db, not everything below it.
+func globMatch(pattern, name string) bool {
+	pattern = strings.Trim(pattern, "/")
+	name = strings.Trim(name, "/")
+
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	if re.MatchString(name) {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		return re.MatchString(name[strings.LastIndex(name, "/")+1:])
+	}
+	return false
+}
+
+// globMatchSegments is the looser match used to filter what tree displays: name
+// also matches when one of its parent paths does, and patterns without a '/' are
+// tried against every segment, so "db" selects whole db branches at any depth.
+// Never use it to pick parameters to change.
+func globMatchSegments(pattern, name string) bool {
+	pattern = strings.Trim(pattern, "/")
+	name = strings.Trim(name, "/")
+
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return false
+	}
+
+	segments := strings.Split(name, "/")
+	for i := range segments {
+		if re.MatchString(strings.Join(segments[:i+1], "/")) {
+			return true
+		}
+		if !strings.Contains(pattern, "/") && re.MatchString(segments[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFilters applies --include / --exclude style glob lists to name.
+// An empty include list matches everything; excludes always win.
+func matchesFilters(name string, include, exclude []string) bool {
+	return filterGlobs(globMatch, name, include, exclude)
+}
+
+// matchesTreeFilters is matchesFilters with globMatchSegments, for display only
+func matchesTreeFilters(name string, include, exclude []string) bool {
+	return filterGlobs(globMatchSegments, name, include, exclude)
+}
+
+func filterGlobs(match func(pattern, name string) bool, name string, include, exclude []string) bool {
+	for _, p := range exclude {
+		if match(p, name) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, p := range include {
+		if match(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				sb.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
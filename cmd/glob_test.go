@@ -0,0 +1,74 @@
+package cmd
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"db/password", "db/password", true},
+		{"/db/password/", "/db/password", true},
+		{"db/*", "db/password", true},
+		{"db/*", "db/nested/password", false},
+		{"db/**", "db/nested/password", true},
+		{"**/password", "app/db/password", true},
+		{"*password*", "app/db/db_password", true},
+		{"*password*", "app/password_dir/host", false},
+		{"db", "db", true},
+		{"db", "app/db", true},
+		{"db", "db/host", false},
+		{"db", "app/db/host", false},
+		{"shared/**", "app/shared/x", false},
+		{"db?", "db1", true},
+		{"db[0-9]", "db7", true},
+		{"db[!0-9]", "db7", false},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGlobMatchSegments(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"db", "db/host", true},
+		{"db", "app/db/host", true},
+		{"db/*", "db/nested/password", true},
+		{"legacy/**", "legacy/a/b", true},
+		{"app/db", "app/db/host", true},
+		{"app/db", "other/app/db/host", false},
+		{"db", "app/dbx/host", false},
+	}
+	for _, tt := range tests {
+		if got := globMatchSegments(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("globMatchSegments(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesFilters(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want, wantTree   bool
+	}{
+		{"app/db/host", nil, nil, true, true},
+		{"app/db/host", []string{"db"}, nil, false, true},
+		{"app/db/host", []string{"app/db/*"}, nil, true, true},
+		{"app/db/host", nil, []string{"db"}, true, false},
+		{"app/db/host", []string{"**"}, []string{"app/db/host"}, false, false},
+	}
+	for _, tt := range tests {
+		if got := matchesFilters(tt.name, tt.include, tt.exclude); got != tt.want {
+			t.Errorf("matchesFilters(%q, %v, %v) = %v, want %v", tt.name, tt.include, tt.exclude, got, tt.want)
+		}
+		if got := matchesTreeFilters(tt.name, tt.include, tt.exclude); got != tt.wantTree {
+			t.Errorf("matchesTreeFilters(%q, %v, %v) = %v, want %v", tt.name, tt.include, tt.exclude, got, tt.wantTree)
+		}
+	}
+}
@@ -1,18 +1,14 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -20,7 +16,18 @@ var (
 	prefix     string
 	secure     bool
 	autoSecure bool
+	scanValues bool
 	overwrite  bool
+	loadKeyID  string
+	loadTags   map[string]string
+	enforce    bool
+
+	// noDescriptions turns off descriptions from and to YAML comments (load and save)
+	noDescriptions bool
+
+	loadExpiration             map[string]string
+	loadExpirationNotification map[string]string
+	loadNoChangeNotification   map[string]string
 )
 
 var loadCmd = &cobra.Command{
@@ -31,30 +38,68 @@ var loadCmd = &cobra.Command{
 		if yamlFile == "" || prefix == "" {
 			return fmt.Errorf("both --file and --prefix are required")
 		}
+		if err := validateNullFlags(); err != nil {
+			return err
+		}
+		if err := validateTierFlag(); err != nil {
+			return err
+		}
 
 		rawYaml, err := os.ReadFile(yamlFile)
 		if err != nil {
 			return fmt.Errorf("failed to read file: %w", err)
 		}
 
-		var data map[string]interface{}
-		decoder := yaml.NewDecoder(bytes.NewReader(rawYaml))
-		if err := decoder.Decode(&data); err != nil {
+		root := "/" + strings.Trim(prefix, "/")
+		leaves, err := yamlLeaves(rawYaml, root)
+		if err != nil {
 			return fmt.Errorf("failed to parse YAML: %w", err)
 		}
 
-		//awsCfg, err := config.LoadDefaultConfig(context.TODO())
-		var cfgOpts []func(*config.LoadOptions) error
-		if awsRegion != "" {
-			cfgOpts = append(cfgOpts, config.WithRegion(awsRegion))
+		// Report everything Parameter Store would reject before the first write
+		problems, err := validateYAML(rawYaml, prefix)
+		if err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			reportProblems(yamlFile, problems)
+			return fmt.Errorf("refusing to upload: %d problem(s) found (see aws-ssm validate)", len(problems))
 		}
-		awsCfg, err := config.LoadDefaultConfig(ctx, cfgOpts...)
+
+		if enforce {
+			if err := checkSecretPolicy(leaves); err != nil {
+				return err
+			}
+		}
+		if !noDescriptions {
+			dropSecretDescriptions(leaves)
+		}
+
+		rules, err := yamlPolicyRules(rawYaml)
 		if err != nil {
-			return fmt.Errorf("failed to load AWS config: %w", err)
+			return fmt.Errorf("failed to read %s policies: %w", yamlMetaKey, err)
 		}
+		policies, err := leafPolicies(leaves, root, append(rules, flagPolicyRules()...))
+		if err != nil {
+			return err
+		}
+		dropPoliciesWithoutAdvanced(policies)
+		reportAdvancedTier(leaves, policies)
 
-		client := ssm.NewFromConfig(awsCfg)
-		return loadConfig(data, prefix, client)
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+		if overwrite {
+			paths := make([]string, 0, len(leaves))
+			for _, leaf := range leaves {
+				paths = append(paths, leaf.Path)
+			}
+			if err := takeBackup("load", paths, client); err != nil {
+				return err
+			}
+		}
+		return loadConfig(leaves, policies, client)
 	},
 }
 
@@ -63,66 +108,171 @@ func init() {
 	loadCmd.Flags().StringVarP(&prefix, "prefix", "p", "", "SSM path prefix (e.g., /myapp) (required)")
 	loadCmd.Flags().BoolVarP(&secure, "secure", "s", false, "Upload all parameters as SecureString")
 	loadCmd.Flags().BoolVarP(&autoSecure, "auto-secure", "a", false, "Auto select SecureString for secret-like keys")
+	loadCmd.Flags().BoolVar(&scanValues, "scan-values", false, "Also select SecureString for values that look like credentials (AWS keys, JWTs, PEM keys, URL passwords, high entropy)")
 	loadCmd.Flags().BoolVarP(&showValues, "values", "v", false, "Show values while uploading")
 	loadCmd.Flags().BoolVarP(&overwrite, "overwrite", "o", false, "Allow overwriting existing parameters")
+	loadCmd.Flags().StringVarP(&loadKeyID, "key-id", "k", "", "KMS key ID for SecureString parameters (defaults to the AWS managed key)")
+	loadCmd.Flags().StringToStringVar(&loadTags, "tag", nil, "Tag every uploaded parameter, as key=value (repeatable)")
+	loadCmd.Flags().BoolVar(&noBackup, "no-backup", false, "Don't write a backup snapshot before overwriting")
+	addNullFlags(loadCmd)
+	addTierFlag(loadCmd)
+	loadCmd.Flags().BoolVar(&noDescriptions, "no-descriptions", false, "Don't use YAML comments as parameter descriptions (otherwise comments are published as plaintext parameter metadata)")
+	loadCmd.Flags().StringToStringVar(&loadExpiration, "expiration", nil, "Expire matching keys (needs --tier advanced), as glob=24h or glob=2025-01-31T00:00:00Z (repeatable)")
+	loadCmd.Flags().StringToStringVar(&loadExpirationNotification, "expiration-notification", nil, "Notify this long before matching keys expire, as glob=4h or glob=2d (repeatable)")
+	loadCmd.Flags().StringToStringVar(&loadNoChangeNotification, "no-change-notification", nil, "Notify when matching keys haven't changed for this long, as glob=30d (repeatable)")
+	loadCmd.Flags().BoolVar(&enforce, "enforce", false, "Refuse to upload anything when a secret-looking leaf would be stored as plain String")
 }
 
-func loadConfig(cfg interface{}, path string, client *ssm.Client) error {
-	switch val := cfg.(type) {
-	case map[string]interface{}:
-		for key, v := range val {
-			childPath := strings.TrimSuffix(path, "/") + "/" + key
-			if err := loadConfig(v, childPath, client); err != nil {
-				return err
-			}
+// loadParamType picks the type load uploads a leaf with
+func loadParamType(path, value string) types.ParameterType {
+	if secure {
+		return types.ParameterTypeSecureString
+	}
+	if flagged, _ := detector.classifyLeaf(path, value, autoSecure, valueScanEnabled()); flagged {
+		return types.ParameterTypeSecureString
+	}
+	return types.ParameterTypeString
+}
+
+// checkSecretPolicy runs before anything is uploaded and lists every leaf the secret
+// detector flags that would still be stored as a plain String
+func checkSecretPolicy(leaves []yamlLeaf) error {
+	violations := 0
+	for _, leaf := range leaves {
+		value, upload := leafValue(leaf)
+		if !upload {
+			continue
 		}
-	case []interface{}:
-		for i, v := range val {
-			childPath := fmt.Sprintf("%s/%d", path, i)
-			if err := loadConfig(v, childPath, client); err != nil {
-				return err
+		flagged, reason := detector.classifyLeaf(leaf.Path, value, true, valueScanEnabled())
+		if !flagged || loadParamType(leaf.Path, value) == types.ParameterTypeSecureString {
+			continue
+		}
+		violations++
+		if emit(event{Operation: "load", Path: leaf.Path, Type: string(types.ParameterTypeString), Result: resultError, ErrorCode: "PolicyViolation", Message: reason}) {
+			continue
+		}
+		location := fmt.Sprintf("%s:%d", yamlFile, leaf.Line)
+		fmt.Fprintf(os.Stderr, "%s %s %s %s\n", icon(markFail), style(roleMuted).Sprint(location), style(roleName).Sprint(leaf.Path), style(roleError).Sprintf("(%s)", reason))
+	}
+	if violations > 0 {
+		return fmt.Errorf("refusing to upload: %d secret-looking value(s) would be stored as String (use --auto-secure or --secure, or pin the keys in secrets.overrides)", violations)
+	}
+	return nil
+}
+
+// leafValue applies --on-null to null and empty leaves and reports whether to upload
+func leafValue(leaf yamlLeaf) (string, bool) {
+	if !leaf.Null && leaf.Value != "" {
+		return leaf.Value, true
+	}
+	switch onNull {
+	case onNullSkip:
+		return "", false
+	case onNullPlaceholder:
+		return nullPlaceholder, true
+	}
+	// onNullError: validation has already refused the file
+	return leaf.Value, true
+}
+
+// yamlLeafValues flattens a YAML document to path -> value exactly as load uploads
+// it. Leaves left out by --on-null skip are listed in skipped, and with --on-null
+// error a null or empty leaf is an error.
+func yamlLeafValues(raw []byte, prefix string) (map[string]string, []string, error) {
+	leaves, err := yamlLeaves(raw, prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	values := make(map[string]string, len(leaves))
+	var skipped []string
+	for _, leaf := range leaves {
+		value, upload := leafValue(leaf)
+		switch {
+		case !upload:
+			skipped = append(skipped, leaf.Path)
+		case onNull == onNullError && (leaf.Null || value == ""):
+			return nil, nil, fmt.Errorf("%s (line %d) is null or empty, use --on-null skip or placeholder", leaf.Path, leaf.Line)
+		default:
+			values[leaf.Path] = value
+		}
+	}
+	return values, skipped, nil
+}
+
+// dropSecretDescriptions runs the value scanner over the descriptions taken from
+// comments and drops the ones that look like they hold a credential: unlike
+// SecureString values, descriptions are stored and returned in plaintext
+func dropSecretDescriptions(leaves []yamlLeaf) {
+	for i, leaf := range leaves {
+		if leaf.Description == "" {
+			continue
+		}
+		secret, reason := detector.classifyValue(leaf.Description)
+		if !secret {
+			continue
+		}
+		leaves[i].Description = ""
+		msg := fmt.Sprintf("comment not used as description, it looks like a secret (%s)", reason)
+		if emit(event{Operation: "load", Path: leaf.Path, Result: resultSkipped, Message: msg}) {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s %s (line %d): %s\n", icon(markWarn), style(roleName).Sprint(leaf.Path), leaf.Line, style(roleWarning).Sprint(msg))
+	}
+}
+
+func loadConfig(leaves []yamlLeaf, policies map[string]string, client *ssm.Client) error {
+	for _, leaf := range leaves {
+		path := leaf.Path
+		valueStr, upload := leafValue(leaf)
+		if !upload {
+			if !emit(event{Operation: "load", Path: path, Result: resultSkipped, Message: "null or empty value"}) {
+				fmt.Fprintf(os.Stderr, "%s Skipping %s (null or empty value)\n", icon(markWarn), style(roleName).Sprint(path))
 			}
+			continue
 		}
-	default:
-		valueStr := fmt.Sprintf("%v", val)
-		paramType := types.ParameterTypeString
-		lockIcon := ""
 
-		if secure {
-			paramType = types.ParameterTypeSecureString
-			lockIcon = " 🔒"
-		} else if autoSecure && isSensitiveKey(path) {
-			paramType = types.ParameterTypeSecureString
-			lockIcon = " 🔒"
+		paramType := loadParamType(path, valueStr)
+		tier := valueTier(valueStr)
+		lockIcon := ""
+		if paramType == types.ParameterTypeSecureString {
+			lockIcon = " " + icon(markSecure)
+		}
+		if tier == types.ParameterTierAdvanced {
+			lockIcon += " " + style(roleMuted).Sprint("(Advanced)")
 		}
 
-		if showValues {
-			fmt.Printf("Uploading %s%s = %s\n", path, lockIcon, valueStr)
-		} else {
-			fmt.Printf("Uploading %s%s\n", path, lockIcon)
+		if !jsonOutput() {
+			if showValues {
+				fmt.Printf("Uploading %s%s = %s\n", path, lockIcon, valueStr)
+			} else {
+				fmt.Printf("Uploading %s%s\n", path, lockIcon)
+			}
 		}
-		_, err := client.PutParameter(ctx, &ssm.PutParameterInput{
+		input := &ssm.PutParameterInput{
 			Name:      aws.String(path),
 			Value:     aws.String(valueStr),
 			Type:      paramType,
+			Tier:      tier,
 			Overwrite: aws.Bool(overwrite),
-		})
+		}
+		if loadKeyID != "" && paramType == types.ParameterTypeSecureString {
+			input.KeyId = aws.String(loadKeyID)
+		}
+		if policies[path] != "" {
+			input.Policies = aws.String(policies[path])
+		}
+		if leaf.Description != "" && !noDescriptions {
+			input.Description = aws.String(leaf.Description)
+		}
+		_, err := client.PutParameter(ctx, input)
+		if err == nil {
+			err = tagParameter(path, loadTags, client)
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Failed to upload %s: %v\n", color.New(color.FgWhite, color.Bold).Sprint(path), color.New(color.FgRed).Sprint(extractMessage(err)))
+			emitError("load", "upload", path, err)
+		} else {
+			emit(event{Operation: "load", Path: path, Type: string(paramType), Result: resultOK})
 		}
-		return nil
-		//return err
 	}
 	return nil
 }
-
-func isSensitiveKey(key string) bool {
-	sensitive := []string{"password", "secret", "token", "key", "apikey", "auth", "private"}
-	key = strings.ToLower(key)
-	for _, s := range sensitive {
-		if strings.Contains(key, s) {
-			return true
-		}
-	}
-	return false
-}
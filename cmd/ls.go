@@ -0,0 +1,363 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lsPrefix    string
+	lsFormat    string
+	lsSort      string
+	lsReverse   bool
+	lsType      string
+	lsTier      string
+	lsOlderThan string
+	lsNewerThan string
+	lsColumns   []string
+)
+
+// lsColumnNames lists the available columns in their default order
+var lsColumnNames = []string{"name", "type", "tier", "version", "modified", "user", "kms", "datatype", "description", "policies"}
+
+var lsCmd = &cobra.Command{
+	Use:     "ls",
+	Short:   "List parameters with their metadata (type, tier, version, last modified, KMS key, ...)",
+	Aliases: []string{"list"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch lsFormat {
+		case "table", "json", "csv":
+		default:
+			return fmt.Errorf("unsupported --format %q (use table, json or csv)", lsFormat)
+		}
+		for _, c := range append([]string{lsSort}, lsColumns...) {
+			if !isLsColumn(c) {
+				return fmt.Errorf("unknown column %q (use %s)", c, strings.Join(lsColumnNames, ", "))
+			}
+		}
+
+		filters, err := lsFilters()
+		if err != nil {
+			return err
+		}
+
+		var olderThan, newerThan time.Time
+		if lsOlderThan != "" {
+			d, err := parseAge(lsOlderThan)
+			if err != nil {
+				return err
+			}
+			olderThan = time.Now().Add(-d)
+		}
+		if lsNewerThan != "" {
+			d, err := parseAge(lsNewerThan)
+			if err != nil {
+				return err
+			}
+			newerThan = time.Now().Add(-d)
+		}
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		all, err := describeParameters(filters, client)
+		if err != nil {
+			return err
+		}
+
+		params := modifiedBetween(all, olderThan, newerThan)
+		sortLs(params, lsSort, lsReverse)
+
+		columns := lsColumns
+		if len(columns) == 0 {
+			columns = lsColumnNames
+		}
+
+		if jsonOutput() {
+			for _, e := range lsEntries(params) {
+				emit(event{Operation: "ls", Path: e.Name, Type: e.Type, Result: resultOK, Data: e})
+			}
+			return nil
+		}
+
+		switch lsFormat {
+		case "json":
+			return writeLsJSON(params)
+		case "csv":
+			return writeLsCSV(params, columns)
+		default:
+			writeLsTable(params, columns)
+		}
+		return nil
+	},
+}
+
+func init() {
+	lsCmd.Flags().StringVarP(&lsPrefix, "prefix", "p", "", "Only list parameters under this prefix (default: whole account)")
+	lsCmd.Flags().StringVarP(&lsFormat, "format", "F", "table", "Output format: table, json or csv")
+	lsCmd.Flags().StringVarP(&lsSort, "sort", "s", "name", fmt.Sprintf("Sort by column (%s)", strings.Join(lsColumnNames, ", ")))
+	lsCmd.Flags().BoolVar(&lsReverse, "reverse", false, "Reverse sort order")
+	lsCmd.Flags().StringVarP(&lsType, "type", "t", "", "Only list this type (String, SecureString, StringList)")
+	lsCmd.Flags().StringVar(&lsTier, "tier", "", "Only list this tier (standard, advanced, intelligent-tiering)")
+	lsCmd.Flags().StringVar(&lsOlderThan, "older-than", "", "Only list parameters last modified before this age (e.g. 90d)")
+	lsCmd.Flags().StringVar(&lsNewerThan, "newer-than", "", "Only list parameters modified within this age (e.g. 24h)")
+	lsCmd.Flags().StringSliceVarP(&lsColumns, "columns", "c", nil, "Columns to show in table/csv output (default all)")
+}
+
+// lsFilters turns --prefix, --type and --tier into DescribeParameters filters
+func lsFilters() ([]types.ParameterStringFilter, error) {
+	var filters []types.ParameterStringFilter
+	if strings.Trim(lsPrefix, "/") != "" {
+		filters = append(filters, types.ParameterStringFilter{
+			Key:    aws.String("Path"),
+			Option: aws.String("Recursive"),
+			Values: []string{"/" + strings.Trim(lsPrefix, "/")},
+		})
+	}
+	if lsType != "" {
+		t, err := parseParameterType(lsType)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, types.ParameterStringFilter{Key: aws.String("Type"), Option: aws.String("Equals"), Values: []string{string(t)}})
+	}
+	if lsTier != "" {
+		t, err := parseParameterTier(lsTier)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, types.ParameterStringFilter{Key: aws.String("Tier"), Option: aws.String("Equals"), Values: []string{string(t)}})
+	}
+	return filters, nil
+}
+
+// modifiedBetween keeps the parameters last modified before olderThan and after
+// newerThan; a zero time leaves that side open
+func modifiedBetween(params []types.ParameterMetadata, olderThan, newerThan time.Time) []types.ParameterMetadata {
+	var kept []types.ParameterMetadata
+	for _, p := range params {
+		modified := aws.ToTime(p.LastModifiedDate)
+		if !olderThan.IsZero() && !modified.Before(olderThan) {
+			continue
+		}
+		if !newerThan.IsZero() && !modified.After(newerThan) {
+			continue
+		}
+		kept = append(kept, p)
+	}
+	return kept
+}
+
+// describeParameters pages through DescribeParameters with the given filters
+func describeParameters(filters []types.ParameterStringFilter, client *ssm.Client) ([]types.ParameterMetadata, error) {
+	var result []types.ParameterMetadata
+	paginator := ssm.NewDescribeParametersPaginator(client, &ssm.DescribeParametersInput{
+		ParameterFilters: filters,
+		MaxResults:       aws.Int32(50),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error describing parameters: %w", err)
+		}
+		result = append(result, page.Parameters...)
+	}
+	return result, nil
+}
+
+func isLsColumn(c string) bool {
+	for _, n := range lsColumnNames {
+		if n == c {
+			return true
+		}
+	}
+	return false
+}
+
+func sortLs(params []types.ParameterMetadata, column string, reverse bool) {
+	sort.SliceStable(params, func(i, j int) bool {
+		if reverse {
+			return lsLess(params[j], params[i], column)
+		}
+		return lsLess(params[i], params[j], column)
+	})
+}
+
+func lsLess(a, b types.ParameterMetadata, column string) bool {
+	switch column {
+	case "version":
+		return a.Version < b.Version
+	case "modified":
+		return aws.ToTime(a.LastModifiedDate).Before(aws.ToTime(b.LastModifiedDate))
+	default:
+		return lsValue(a, column, false) < lsValue(b, column, false)
+	}
+}
+
+// lsValue renders one cell; short trims long values for the table view
+func lsValue(p types.ParameterMetadata, column string, short bool) string {
+	switch column {
+	case "name":
+		return aws.ToString(p.Name)
+	case "type":
+		return string(p.Type)
+	case "tier":
+		return string(p.Tier)
+	case "version":
+		return strconv.FormatInt(p.Version, 10)
+	case "modified":
+		if p.LastModifiedDate == nil {
+			return ""
+		}
+		if short {
+			return p.LastModifiedDate.Local().Format("2006-01-02 15:04")
+		}
+		return p.LastModifiedDate.UTC().Format(time.RFC3339)
+	case "user":
+		user := aws.ToString(p.LastModifiedUser)
+		if short {
+			// arn:aws:iam::123456789012:user/alice -> user/alice
+			if parts := strings.SplitN(user, ":", 6); len(parts) == 6 {
+				user = parts[5]
+			}
+		}
+		return user
+	case "kms":
+		return aws.ToString(p.KeyId)
+	case "datatype":
+		return aws.ToString(p.DataType)
+	case "description":
+		desc := aws.ToString(p.Description)
+		if short {
+			desc = strings.ReplaceAll(desc, "\n", " ")
+			if r := []rune(desc); len(r) > 40 {
+				desc = string(r[:39]) + "…"
+			}
+		}
+		return desc
+	case "policies":
+		return strings.Join(describePolicies(p.Policies), ", ")
+	}
+	return ""
+}
+
+func writeLsTable(params []types.ParameterMetadata, columns []string) {
+	rows := make([][]string, 0, len(params))
+	for _, p := range params {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = lsValue(p, c, true)
+			if c == "name" && p.Type == types.ParameterTypeSecureString {
+				row[i] += " " + icon(markSecure)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = displayWidth(c)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], displayWidth(cell))
+		}
+	}
+
+	pad := func(s string, width int) string {
+		return s + strings.Repeat(" ", max(width-displayWidth(s), 0))
+	}
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = style(roleHeading).Sprint(pad(strings.ToUpper(c), widths[i]))
+	}
+	fmt.Println(strings.Join(header, "  "))
+
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = pad(cell, widths[i])
+			switch {
+			case columns[i] == "name" && params[r].Type == types.ParameterTypeSecureString:
+				cells[i] = style(roleSecure).Sprint(cells[i])
+			case columns[i] == "name":
+				cells[i] = style(roleString).Sprint(cells[i])
+			default:
+				cells[i] = style(roleMuted).Sprint(cells[i])
+			}
+		}
+		fmt.Println(strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+
+	fmt.Printf("\n%d parameters\n", len(params))
+}
+
+func writeLsCSV(params []types.ParameterMetadata, columns []string) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(columns); err != nil {
+		return err
+	}
+	for _, p := range params {
+		record := make([]string, len(columns))
+		for i, c := range columns {
+			record[i] = lsValue(p, c, false)
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+type lsEntry struct {
+	Name             string     `json:"name"`
+	Type             string     `json:"type"`
+	Tier             string     `json:"tier"`
+	Version          int64      `json:"version"`
+	LastModifiedDate *time.Time `json:"last_modified_date,omitempty"`
+	LastModifiedUser string     `json:"last_modified_user,omitempty"`
+	KeyID            string     `json:"key_id,omitempty"`
+	DataType         string     `json:"data_type,omitempty"`
+	Description      string     `json:"description,omitempty"`
+	Policies         []string   `json:"policies,omitempty"`
+}
+
+func lsEntries(params []types.ParameterMetadata) []lsEntry {
+	entries := make([]lsEntry, 0, len(params))
+	for _, p := range params {
+		entries = append(entries, lsEntry{
+			Name:             aws.ToString(p.Name),
+			Type:             string(p.Type),
+			Tier:             string(p.Tier),
+			Version:          p.Version,
+			LastModifiedDate: p.LastModifiedDate,
+			LastModifiedUser: aws.ToString(p.LastModifiedUser),
+			KeyID:            aws.ToString(p.KeyId),
+			DataType:         aws.ToString(p.DataType),
+			Description:      aws.ToString(p.Description),
+			Policies:         describePolicies(p.Policies),
+		})
+	}
+	return entries
+}
+
+func writeLsJSON(params []types.ParameterMetadata) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(lsEntries(params))
+}
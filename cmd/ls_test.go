@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestLsFilters(t *testing.T) {
+	tests := []struct {
+		prefix, typ, tier string
+		want              []string
+		wantErr           bool
+	}{
+		{"", "", "", nil, false},
+		{"/", "", "", nil, false},
+		{"app/dev/", "", "", []string{"Path Recursive /app/dev"}, false},
+		{"/app", "securestring", "advanced", []string{"Path Recursive /app", "Type Equals SecureString", "Tier Equals Advanced"}, false},
+		{"", "", "intelligent", []string{"Tier Equals Intelligent-Tiering"}, false},
+		{"", "secret", "", nil, true},
+		{"", "", "premium", nil, true},
+	}
+	defer func(p, ty, ti string) { lsPrefix, lsType, lsTier = p, ty, ti }(lsPrefix, lsType, lsTier)
+	for _, tt := range tests {
+		lsPrefix, lsType, lsTier = tt.prefix, tt.typ, tt.tier
+		filters, err := lsFilters()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q %q %q: error = %v, want error %v", tt.prefix, tt.typ, tt.tier, err, tt.wantErr)
+			continue
+		}
+		var got []string
+		for _, f := range filters {
+			got = append(got, aws.ToString(f.Key)+" "+aws.ToString(f.Option)+" "+f.Values[0])
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q %q %q: got %v, want %v", tt.prefix, tt.typ, tt.tier, got, tt.want)
+		}
+	}
+}
+
+func TestModifiedBetween(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	param := func(name string, age time.Duration) types.ParameterMetadata {
+		modified := now.Add(-age)
+		return types.ParameterMetadata{Name: aws.String(name), LastModifiedDate: &modified}
+	}
+	params := []types.ParameterMetadata{param("/new", time.Hour), param("/week", 7*day), param("/old", 100*day)}
+	tests := []struct {
+		olderThan, newerThan time.Time
+		want                 []string
+	}{
+		{time.Time{}, time.Time{}, []string{"/new", "/week", "/old"}},
+		{now.Add(-90 * day), time.Time{}, []string{"/old"}},
+		{time.Time{}, now.Add(-day), []string{"/new"}},
+		{now.Add(-day), now.Add(-30 * day), []string{"/week"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, p := range modifiedBetween(params, tt.olderThan, tt.newerThan) {
+			got = append(got, aws.ToString(p.Name))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("older than %v, newer than %v: got %v, want %v", tt.olderThan, tt.newerThan, got, tt.want)
+		}
+	}
+}
+
+func TestSortLs(t *testing.T) {
+	at := func(day int) *time.Time {
+		t := time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	params := []types.ParameterMetadata{
+		{Name: aws.String("/b"), Version: 10, LastModifiedDate: at(1), Type: types.ParameterTypeString},
+		{Name: aws.String("/a"), Version: 2, LastModifiedDate: at(3), Type: types.ParameterTypeSecureString},
+		{Name: aws.String("/c"), Version: 9, LastModifiedDate: at(2), Type: types.ParameterTypeString},
+	}
+	tests := []struct {
+		column  string
+		reverse bool
+		want    []string
+	}{
+		{"name", false, []string{"/a", "/b", "/c"}},
+		{"name", true, []string{"/c", "/b", "/a"}},
+		{"version", false, []string{"/a", "/c", "/b"}},
+		{"modified", false, []string{"/b", "/c", "/a"}},
+		{"type", false, []string{"/a", "/b", "/c"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]types.ParameterMetadata{}, params...)
+		sortLs(sorted, tt.column, tt.reverse)
+		var got []string
+		for _, p := range sorted {
+			got = append(got, aws.ToString(p.Name))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("--sort %s reverse=%v: got %v, want %v", tt.column, tt.reverse, got, tt.want)
+		}
+	}
+}
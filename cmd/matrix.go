@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	matrixPrefixes []string
+	matrixFormat   string
+	matrixOutFile  string
+	matrixDecrypt  bool
+	matrixGapsOnly bool
+)
+
+var matrixCmd = &cobra.Command{
+	Use:     "matrix [prefix...]",
+	Short:   "Compare parameter keys across several prefixes (environments)",
+	Long:    "Compare parameter keys across several prefixes (environments). Prefixes may contain globs, e.g. '/myapp/*'",
+	Aliases: []string{"m", "mx"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		patterns := append(append([]string{}, matrixPrefixes...), args...)
+		if len(patterns) == 0 {
+			return fmt.Errorf("at least one --prefix (or prefix argument) is required")
+		}
+
+		switch matrixFormat {
+		case "table", "csv", "markdown", "md":
+		default:
+			return fmt.Errorf("unsupported --format %q (use table, csv or markdown)", matrixFormat)
+		}
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		envs, params, err := resolveMatrixPrefixes(patterns, client)
+		if err != nil {
+			return err
+		}
+		if len(envs) == 0 {
+			return fmt.Errorf("no prefixes matched %s", strings.Join(patterns, ", "))
+		}
+
+		m := buildMatrix(envs, params)
+		if matrixGapsOnly {
+			m.rows = m.gapRows()
+		}
+
+		if jsonOutput() {
+			for _, r := range m.rows {
+				cells := make(map[string]string, len(m.envs))
+				for i, env := range m.envs {
+					cells[env] = r.cells[i].text()
+				}
+				emit(event{Operation: "matrix", Path: r.key, Result: resultOK, Data: cells})
+			}
+			return nil
+		}
+
+		var out io.Writer = os.Stdout
+		if matrixOutFile != "" && matrixOutFile != "-" {
+			f, err := os.Create(matrixOutFile)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		switch matrixFormat {
+		case "csv":
+			return m.writeCSV(out)
+		case "markdown", "md":
+			m.writeMarkdown(out)
+		default:
+			m.writeTable(out)
+		}
+		return nil
+	},
+}
+
+func init() {
+	matrixCmd.Flags().StringSliceVarP(&matrixPrefixes, "prefix", "p", nil, "SSM path prefix or glob to compare (repeatable, e.g. /myapp/*)")
+	matrixCmd.Flags().StringVarP(&matrixFormat, "format", "F", "table", "Output format: table, csv or markdown")
+	matrixCmd.Flags().StringVarP(&matrixOutFile, "out", "o", "", "Output file (defaults to stdout)")
+	matrixCmd.Flags().BoolVarP(&matrixDecrypt, "decrypt", "d", false, "Decrypt SecureString values so they take part in the majority comparison")
+	matrixCmd.Flags().BoolVarP(&matrixGapsOnly, "gaps", "g", false, "Only show keys that are missing or differing somewhere")
+}
+
+type matrixCell struct {
+	present bool
+	secure  bool
+	differs bool
+}
+
+type matrixRow struct {
+	key   string
+	cells []matrixCell
+}
+
+type matrix struct {
+	envs []string
+	rows []matrixRow
+}
+
+// resolveMatrixPrefixes expands glob patterns into concrete prefixes and returns
+// every parameter found below them, keyed by full name
+func resolveMatrixPrefixes(patterns []string, client *ssm.Client) ([]string, map[string]treeParam, error) {
+	params := make(map[string]treeParam)
+	fetched := make(map[string]bool)
+	seen := make(map[string]bool)
+	var envs []string
+
+	fetch := func(base string) error {
+		if fetched[base] {
+			return nil
+		}
+		fetched[base] = true
+		found, err := fetchAllParameterObjects(base, matrixDecrypt, client)
+		if err != nil {
+			return err
+		}
+		for k, v := range found {
+			params[k] = v
+		}
+		return nil
+	}
+
+	for _, pattern := range patterns {
+		pattern = "/" + strings.Trim(pattern, "/")
+		if !strings.ContainsAny(pattern, "*?[") {
+			if err := fetch(pattern); err != nil {
+				return nil, nil, err
+			}
+			if !seen[pattern] {
+				seen[pattern] = true
+				envs = append(envs, pattern)
+			}
+			continue
+		}
+
+		// Fetch from the longest literal parent and match candidates segment by segment
+		segs := strings.Split(strings.Trim(pattern, "/"), "/")
+		base := ""
+		for _, s := range segs {
+			if strings.ContainsAny(s, "*?[") {
+				break
+			}
+			base += "/" + s
+		}
+		if base == "" {
+			base = "/"
+		}
+		if err := fetch(base); err != nil {
+			return nil, nil, err
+		}
+
+		var matched []string
+		for name := range params {
+			nameSegs := strings.Split(strings.Trim(name, "/"), "/")
+			// The environment must contain at least one parameter below it
+			if len(nameSegs) <= len(segs) {
+				continue
+			}
+			candidate := "/" + strings.Join(nameSegs[:len(segs)], "/")
+			ok, err := path.Match(pattern, candidate)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid prefix pattern %q: %w", pattern, err)
+			}
+			if ok && !seen[candidate] {
+				seen[candidate] = true
+				matched = append(matched, candidate)
+			}
+		}
+		sort.Strings(matched)
+		envs = append(envs, matched...)
+	}
+
+	return envs, params, nil
+}
+
+func buildMatrix(envs []string, params map[string]treeParam) *matrix {
+	type entry struct {
+		param treeParam
+		ok    bool
+	}
+
+	byKey := make(map[string][]entry)
+	for name, p := range params {
+		for i, env := range envs {
+			if !strings.HasPrefix(name, env+"/") {
+				continue
+			}
+			rel := strings.TrimPrefix(name, env+"/")
+			if _, ok := byKey[rel]; !ok {
+				byKey[rel] = make([]entry, len(envs))
+			}
+			byKey[rel][i] = entry{param: p, ok: true}
+		}
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	m := &matrix{envs: envs}
+	for _, k := range keys {
+		entries := byKey[k]
+		row := matrixRow{key: k, cells: make([]matrixCell, len(envs))}
+
+		// Find the majority value among environments whose value is comparable
+		counts := make(map[string]int)
+		for _, e := range entries {
+			if e.ok && (matrixDecrypt || e.param.Type != types.ParameterTypeSecureString) {
+				counts[e.param.Value]++
+			}
+		}
+		majority, best, tie := "", 0, false
+		for v, c := range counts {
+			switch {
+			case c > best:
+				majority, best, tie = v, c, false
+			case c == best:
+				tie = true
+			}
+		}
+		hasMajority := best > 1 && !tie
+
+		for i, e := range entries {
+			if !e.ok {
+				continue
+			}
+			cell := matrixCell{
+				present: true,
+				secure:  e.param.Type == types.ParameterTypeSecureString,
+			}
+			if hasMajority && (matrixDecrypt || !cell.secure) {
+				cell.differs = e.param.Value != majority
+			}
+			row.cells[i] = cell
+		}
+		m.rows = append(m.rows, row)
+	}
+
+	return m
+}
+
+func (m *matrix) gapRows() []matrixRow {
+	var rows []matrixRow
+	for _, r := range m.rows {
+		for _, c := range r.cells {
+			if !c.present || c.differs {
+				rows = append(rows, r)
+				break
+			}
+		}
+	}
+	return rows
+}
+
+func (c matrixCell) text() string {
+	switch {
+	case !c.present:
+		return "missing"
+	case c.secure && c.differs:
+		return "secure (differs)"
+	case c.secure:
+		return "secure"
+	case c.differs:
+		return "present (differs)"
+	default:
+		return "present"
+	}
+}
+
+func (c matrixCell) symbol() string {
+	switch {
+	case !c.present:
+		return "✗"
+	case c.secure && c.differs:
+		return icon(markSecure) + "≠"
+	case c.secure:
+		return icon(markSecure)
+	case c.differs:
+		return "≠"
+	default:
+		return "✓"
+	}
+}
+
+func (c matrixCell) colored(s string) string {
+	switch {
+	case !c.present:
+		return style(roleError).Sprint(s)
+	case c.differs:
+		return style(roleWarning).Sprint(s)
+	case c.secure:
+		return style(roleSecure).Sprint(s)
+	default:
+		return style(roleSuccess).Sprint(s)
+	}
+}
+
+func (m *matrix) writeTable(w io.Writer) {
+	widths := make([]int, len(m.envs)+1)
+	widths[0] = displayWidth("key")
+	for _, r := range m.rows {
+		widths[0] = max(widths[0], displayWidth(r.key))
+	}
+	for i, env := range m.envs {
+		widths[i+1] = max(displayWidth(env), 3)
+	}
+
+	pad := func(s string, width int) string {
+		return s + strings.Repeat(" ", max(width-displayWidth(s), 0))
+	}
+
+	header := []string{style(roleHeading).Sprint(pad("key", widths[0]))}
+	for i, env := range m.envs {
+		header = append(header, style(roleHeading).Sprint(pad(env, widths[i+1])))
+	}
+	fmt.Fprintln(w, strings.Join(header, "  "))
+
+	gaps := 0
+	for _, r := range m.rows {
+		line := []string{pad(r.key, widths[0])}
+		gap := false
+		for i, c := range r.cells {
+			line = append(line, c.colored(pad(c.symbol(), widths[i+1])))
+			gap = gap || !c.present || c.differs
+		}
+		if gap {
+			gaps++
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(line, "  "), " "))
+	}
+
+	fmt.Fprintf(w, "\n%d keys across %d prefixes, %d with gaps or differences (✓ present, %s SecureString, ≠ differs from majority, ✗ missing)\n", len(m.rows), len(m.envs), gaps, icon(markSecure))
+}
+
+func (m *matrix) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"key"}, m.envs...)); err != nil {
+		return err
+	}
+	for _, r := range m.rows {
+		record := []string{r.key}
+		for _, c := range r.cells {
+			record = append(record, c.text())
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (m *matrix) writeMarkdown(w io.Writer) {
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "|", "\\|")
+	}
+
+	header := []string{"key"}
+	sep := []string{"---"}
+	for _, env := range m.envs {
+		header = append(header, "`"+escape(env)+"`")
+		sep = append(sep, ":---:")
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(w, "| %s |\n", strings.Join(sep, " | "))
+
+	for _, r := range m.rows {
+		line := []string{"`" + escape(r.key) + "`"}
+		for _, c := range r.cells {
+			line = append(line, c.text())
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(line, " | "))
+	}
+}
+
+// displayWidth approximates the terminal width of s, counting emoji as two columns
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		if r >= 0x1F000 {
+			w += 2
+		} else {
+			w++
+		}
+	}
+	return w
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestBuildMatrix(t *testing.T) {
+	plain := func(v string) treeParam { return treeParam{Type: types.ParameterTypeString, Value: v} }
+	secret := func(v string) treeParam { return treeParam{Type: types.ParameterTypeSecureString, Value: v} }
+	envs := []string{"/app/dev", "/app/staging", "/app/prod"}
+	params := map[string]treeParam{
+		"/app/dev/same": plain("x"), "/app/staging/same": plain("x"), "/app/prod/same": plain("x"),
+		"/app/dev/odd": plain("a"), "/app/staging/odd": plain("a"), "/app/prod/odd": plain("b"),
+		"/app/dev/tie": plain("a"), "/app/staging/tie": plain("b"),
+		"/app/dev/all": plain("a"), "/app/staging/all": plain("b"), "/app/prod/all": plain("c"),
+		"/app/dev/secret": secret("s1"), "/app/staging/secret": secret("s1"), "/app/prod/secret": secret("s2"),
+		"/app/prod/only":    plain("x"),
+		"/app/devx/ignored": plain("x"),
+	}
+	tests := []struct {
+		decrypt bool
+		want    map[string][]string
+	}{
+		{false, map[string][]string{
+			"all":    {"present", "present", "present"},
+			"odd":    {"present", "present", "present (differs)"},
+			"only":   {"missing", "missing", "present"},
+			"same":   {"present", "present", "present"},
+			"secret": {"secure", "secure", "secure"},
+			"tie":    {"present", "present", "missing"},
+		}},
+		{true, map[string][]string{
+			"all":    {"present", "present", "present"},
+			"odd":    {"present", "present", "present (differs)"},
+			"only":   {"missing", "missing", "present"},
+			"same":   {"present", "present", "present"},
+			"secret": {"secure", "secure", "secure (differs)"},
+			"tie":    {"present", "present", "missing"},
+		}},
+	}
+	defer func(d bool) { matrixDecrypt = d }(matrixDecrypt)
+	for _, tt := range tests {
+		matrixDecrypt = tt.decrypt
+		m := buildMatrix(envs, params)
+		if len(m.rows) != len(tt.want) {
+			t.Fatalf("decrypt=%v: got %d rows, want %d", tt.decrypt, len(m.rows), len(tt.want))
+		}
+		for _, r := range m.rows {
+			want, ok := tt.want[r.key]
+			if !ok {
+				t.Errorf("decrypt=%v: unexpected row %q", tt.decrypt, r.key)
+				continue
+			}
+			for i, c := range r.cells {
+				if c.text() != want[i] {
+					t.Errorf("decrypt=%v: %s in %s = %q, want %q", tt.decrypt, r.key, envs[i], c.text(), want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestMatrixOutput(t *testing.T) {
+	m := &matrix{
+		envs: []string{"/app/dev", "/app/prod"},
+		rows: []matrixRow{
+			{key: "db/host", cells: []matrixCell{{present: true}, {present: true, differs: true}}},
+			{key: "a|b", cells: []matrixCell{{present: true, secure: true}, {}}},
+			{key: "ok", cells: []matrixCell{{present: true}, {present: true}}},
+		},
+	}
+	if got := len(m.gapRows()); got != 2 {
+		t.Errorf("gapRows() returned %d rows, want 2", got)
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"csv", "key,/app/dev,/app/prod\n" +
+			"db/host,present,present (differs)\n" +
+			"a|b,secure,missing\n" +
+			"ok,present,present\n"},
+		{"markdown", "| key | `/app/dev` | `/app/prod` |\n" +
+			"| --- | :---: | :---: |\n" +
+			"| `db/host` | present | present (differs) |\n" +
+			"| `a\\|b` | secure | missing |\n" +
+			"| `ok` | present | present |\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if tt.format == "csv" {
+			if err := m.writeCSV(&buf); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			m.writeMarkdown(&buf)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s:\ngot\n%s\nwant\n%s", tt.format, buf.String(), tt.want)
+		}
+	}
+}
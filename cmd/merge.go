@@ -0,0 +1,447 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	mergeInto  string
+	mergePrune bool
+)
+
+// mergeResult counts what mergeIntoYAML changed
+type mergeResult struct {
+	updated, added, removed []string
+	// stale are keys of the file that are no longer in SSM, kept without --prune
+	stale []string
+}
+
+// mergeIntoYAML updates the YAML document in file with params (full names below
+// savePrefix) in place, so key order, comments and anchors survive. The result is
+// written back to file, or to out when given ("-" is stdout).
+func mergeIntoYAML(file, out string, params, descriptions map[string]string) error {
+	if out == "-" && jsonOutput() {
+		return fmt.Errorf("--merge-into can't write the document to stdout with --output json")
+	}
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if documentRoot(&doc).Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: the document must be a mapping", file)
+	}
+
+	root := "/" + strings.Trim(savePrefix, "/")
+	wanted := make(map[string]string, len(params))
+	for name, value := range params {
+		wanted[strings.TrimPrefix(strings.TrimPrefix(name, root), "/")] = value
+	}
+
+	result, err := mergeNodes(&doc, wanted, descriptions, root)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	untagMergeKeys(&doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(yamlIndent(raw))
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	switch out {
+	case "-":
+		os.Stdout.Write(buf.Bytes())
+	case "":
+		out = file
+		fallthrough
+	default:
+		if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", out, err)
+		}
+	}
+
+	reportMerge(out, root, result)
+	return nil
+}
+
+// mergeNodes applies wanted (relative path -> value) to doc. Values reached through
+// an alias or a "<<" merge key are overridden locally instead of changing the anchor,
+// which other keys may share, so it repeats until every path reads back as wanted.
+func mergeNodes(doc *yaml.Node, wanted, descriptions map[string]string, root string) (*mergeResult, error) {
+	result := &mergeResult{}
+	current := currentLeaves(doc)
+
+	for _, path := range sortedMergePaths(current, mergePrune) {
+		if _, ok := wanted[path]; ok {
+			continue
+		}
+		if !mergePrune {
+			result.stale = append(result.stale, path)
+		} else if removeYAMLPath(documentRoot(doc), strings.Split(path, "/")) {
+			result.removed = append(result.removed, path)
+		}
+	}
+
+	for _, path := range sortedKeys(wanted) {
+		if _, ok := current[path]; !ok {
+			result.added = append(result.added, path)
+		}
+	}
+
+	for pass := 0; ; pass++ {
+		current = currentLeaves(doc)
+		var pending []string
+		for _, path := range sortedKeys(wanted) {
+			if leaf, ok := current[path]; !ok || !sameScalar(leaf, wanted[path]) {
+				pending = append(pending, path)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		if pass == 10 {
+			return nil, fmt.Errorf("could not merge %s", strings.Join(pending, ", "))
+		}
+		for _, path := range pending {
+			if _, existed := current[path]; existed && pass == 0 {
+				result.updated = append(result.updated, path)
+			}
+			desc := ""
+			if _, existed := current[path]; !existed {
+				desc = descriptions[root+"/"+path]
+			}
+			if err := setYAMLPath(documentRoot(doc), strings.Split(path, "/"), parseTypedValue(wanted[path]), desc); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// currentLeaves maps the relative path of every leaf of doc to its node
+func currentLeaves(doc *yaml.Node) map[string]*yaml.Node {
+	leaves := make(map[string]*yaml.Node)
+	walkYAMLLeaves(doc, "", func(path string, _, leaf *yaml.Node) {
+		leaves[strings.TrimPrefix(path, "/")] = leaf
+	})
+	return leaves
+}
+
+// sameScalar reports whether leaf already holds value as save would write it
+func sameScalar(leaf *yaml.Node, value string) bool {
+	if parseTypedValue(value) == nil {
+		return isNullNode(leaf)
+	}
+	return !isNullNode(leaf) && leaf.Value == value
+}
+
+// setYAMLPath writes value at segments below the container n, creating missing keys
+// and replacing aliases and merged keys on the way by local copies
+func setYAMLPath(n *yaml.Node, segments []string, value interface{}, desc string) error {
+	seg, last := segments[0], len(segments) == 1
+
+	var child *yaml.Node
+	switch n.Kind {
+	case yaml.MappingNode:
+		i := mappingIndex(n, seg)
+		if i < 0 {
+			key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: seg}
+			switch merged := mergedValue(n, seg); {
+			case last:
+				child = newScalarNode(value)
+				if desc != "" {
+					setLeafComment(key, child, desc)
+				}
+			case merged != nil:
+				child = copyYAMLNode(merged)
+			default:
+				child = newContainerNode(segments[1])
+			}
+			insertMappingKey(n, key, child)
+			i = mappingIndex(n, seg)
+		}
+		child = localNode(&n.Content[i], segments)
+	case yaml.SequenceNode:
+		idx, err := strconv.Atoi(seg)
+		if err != nil || idx < 0 {
+			return fmt.Errorf("%q is not a list index", seg)
+		}
+		if idx >= len(n.Content) {
+			if last {
+				n.Content = append(n.Content, newScalarNode(value))
+			} else {
+				n.Content = append(n.Content, newContainerNode(segments[1]))
+			}
+			idx = len(n.Content) - 1
+		}
+		child = localNode(&n.Content[idx], segments)
+	default:
+		return fmt.Errorf("can't set %q below a scalar", seg)
+	}
+
+	if last {
+		setScalarNode(child, value)
+		return nil
+	}
+	return setYAMLPath(child, segments[1:], value, desc)
+}
+
+// localNode makes *slot safe to change for the rest of segments: aliases are
+// replaced by copies and scalars in the way of deeper keys by containers
+func localNode(slot **yaml.Node, segments []string) *yaml.Node {
+	n := *slot
+	last := len(segments) == 1
+	switch {
+	case last && n.Kind != yaml.ScalarNode:
+		n = &yaml.Node{Kind: yaml.ScalarNode, HeadComment: n.HeadComment, LineComment: n.LineComment}
+	case n.Kind == yaml.AliasNode:
+		n = copyYAMLNode(n.Alias)
+	case !last && n.Kind == yaml.ScalarNode:
+		n = newContainerNode(segments[1])
+	}
+	*slot = n
+	return n
+}
+
+// setScalarNode changes the value of a scalar, keeping its comments, anchor and
+// quoting where that still fits the new value
+func setScalarNode(n *yaml.Node, value interface{}) {
+	fresh := newScalarNode(value)
+	n.Tag, n.Value = fresh.Tag, fresh.Value
+	switch {
+	case strings.Contains(n.Value, "\n"):
+		n.Style = yaml.LiteralStyle
+	case n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0, fresh.Tag != "!!str", n.Style == 0:
+		n.Style = fresh.Style
+	}
+}
+
+func newScalarNode(value interface{}) *yaml.Node {
+	var n yaml.Node
+	if err := n.Encode(value); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(value)}
+	}
+	return &n
+}
+
+// newContainerNode returns the node holding next: a list for indexes, like save
+// writes them (unless --raw), otherwise a mapping
+func newContainerNode(next string) *yaml.Node {
+	if _, err := strconv.Atoi(next); err == nil && !rawOutput {
+		return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+// mappingIndex returns the index of the value of key in n.Content, or -1
+func mappingIndex(n *yaml.Node, key string) int {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key && n.Content[i].Tag != "!!merge" {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// mergedValue looks key up in the mappings merged into n with "<<", with the same
+// precedence as walkYAMLLeaves
+func mergedValue(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if !isMergeKey(n.Content[i]) {
+			continue
+		}
+		for _, src := range mergeSources(n.Content[i+1]) {
+			for _, e := range mappingEntries(src) {
+				if e.key.Value != key {
+					continue
+				}
+				if e.value.Kind == yaml.AliasNode {
+					return e.value.Alias
+				}
+				return e.value
+			}
+		}
+	}
+	return nil
+}
+
+// insertMappingKey adds a key in alphabetical position when the mapping is sorted
+// already, otherwise at the end
+func insertMappingKey(n *yaml.Node, key, value *yaml.Node) {
+	var keys []string
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Tag != "!!merge" {
+			keys = append(keys, n.Content[i].Value)
+		}
+	}
+	pos := len(n.Content)
+	if sort.StringsAreSorted(keys) {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Tag != "!!merge" && n.Content[i].Value > key.Value {
+				pos = i
+				break
+			}
+		}
+	}
+	content := append([]*yaml.Node{}, n.Content[:pos]...)
+	content = append(content, key, value)
+	n.Content = append(content, n.Content[pos:]...)
+}
+
+// copyYAMLNode deep copies n without anchors, so the copy can be changed on its own
+func copyYAMLNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	c.Anchor = ""
+	c.Content = nil
+	for _, child := range n.Content {
+		if child.Kind == yaml.AliasNode {
+			c.Content = append(c.Content, child)
+			continue
+		}
+		c.Content = append(c.Content, copyYAMLNode(child))
+	}
+	return &c
+}
+
+// removeYAMLPath deletes the leaf at segments and any container left empty by it.
+// Keys that come from an alias or a merge are shared and left alone.
+func removeYAMLPath(n *yaml.Node, segments []string) bool {
+	var i int
+	switch n.Kind {
+	case yaml.MappingNode:
+		if i = mappingIndex(n, segments[0]); i < 0 {
+			return false
+		}
+	case yaml.SequenceNode:
+		idx, err := strconv.Atoi(segments[0])
+		if err != nil || idx < 0 || idx >= len(n.Content) {
+			return false
+		}
+		i = idx
+	default:
+		return false
+	}
+
+	child := n.Content[i]
+	if len(segments) > 1 {
+		if child.Kind == yaml.AliasNode || !removeYAMLPath(child, segments[1:]) {
+			return false
+		}
+		if len(child.Content) > 0 {
+			return true
+		}
+	}
+	if n.Kind == yaml.MappingNode {
+		n.Content = append(n.Content[:i-1], n.Content[i+1:]...)
+	} else {
+		n.Content = append(n.Content[:i], n.Content[i+1:]...)
+	}
+	return true
+}
+
+// sortedMergePaths sorts paths with list indexes in numeric order, reversed when
+// desc is set so removing list items doesn't shift the ones still to remove
+func sortedMergePaths(leaves map[string]*yaml.Node, desc bool) []string {
+	paths := make([]string, 0, len(leaves))
+	for p := range leaves {
+		paths = append(paths, p)
+	}
+	less := func(a, b string) bool {
+		as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+		for i := 0; i < len(as) && i < len(bs); i++ {
+			if as[i] == bs[i] {
+				continue
+			}
+			ai, aErr := strconv.Atoi(as[i])
+			bi, bErr := strconv.Atoi(bs[i])
+			if aErr == nil && bErr == nil {
+				return ai < bi
+			}
+			return as[i] < bs[i]
+		}
+		return len(as) < len(bs)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if desc {
+			return less(paths[j], paths[i])
+		}
+		return less(paths[i], paths[j])
+	})
+	return paths
+}
+
+// untagMergeKeys clears the resolved tag of "<<" keys, which the encoder would
+// otherwise write out as "!!merge <<"
+func untagMergeKeys(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if k := n.Content[i]; k.Tag == "!!merge" && k.Value == "<<" {
+				k.Tag = ""
+			}
+		}
+	}
+	for _, c := range n.Content {
+		untagMergeKeys(c)
+	}
+}
+
+// yamlIndent guesses the indentation of a document from its first indented line
+func yamlIndent(raw []byte) int {
+	for _, line := range strings.Split(string(raw), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if n := len(line) - len(trimmed); n >= 2 && n <= 8 {
+			return n
+		}
+		break
+	}
+	return 2
+}
+
+func reportMerge(file, root string, r *mergeResult) {
+	if jsonOutput() {
+		for _, changes := range []struct {
+			paths   []string
+			message string
+		}{{r.updated, "updated"}, {r.added, "added"}, {r.removed, "removed"}} {
+			for _, p := range changes.paths {
+				emit(event{Operation: "save", Path: root + "/" + p, Result: resultOK, Message: changes.message})
+			}
+		}
+		for _, p := range r.stale {
+			emit(event{Operation: "save", Path: root + "/" + p, Result: resultSkipped, Message: "not in SSM (use --prune to remove)"})
+		}
+		emit(event{Operation: "save", Path: file, Result: resultOK, Message: "merged"})
+		return
+	}
+
+	out := humanOut()
+	if file == "-" {
+		// the document itself went to stdout
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "%s Merged into %s: %d updated, %d added, %d removed\n", icon(markOK), style(roleName).Sprint(file), len(r.updated), len(r.added), len(r.removed))
+	if len(r.stale) > 0 {
+		fmt.Fprintf(out, "%s %d key(s) are no longer in SSM, use --prune to remove them:\n", icon(markWarn), len(r.stale))
+		for _, p := range r.stale {
+			fmt.Fprintf(out, "  %s\n", style(roleMuted).Sprint(p))
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergeYAML runs mergeNodes on raw and encodes the result like mergeIntoYAML
+func mergeYAML(t *testing.T, raw string, wanted, descriptions map[string]string, prune bool) (string, *mergeResult) {
+	t.Helper()
+	defer func(p bool) { mergePrune = p }(mergePrune)
+	mergePrune = prune
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatal(err)
+	}
+	result, err := mergeNodes(&doc, wanted, descriptions, "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	untagMergeKeys(&doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(yamlIndent([]byte(raw)))
+	if err := enc.Encode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String(), result
+}
+
+func TestMergeNodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		yaml         string
+		wanted       map[string]string
+		descriptions map[string]string
+		prune        bool
+		want         string
+		updated      []string
+		added        []string
+		removed      []string
+		stale        []string
+	}{
+		{
+			name:    "update keeps comments, order and quoting",
+			yaml:    "# app config\nzeta: 1\ndb:\n  # endpoint\n  host: \"old.internal\"  # primary\n  port: 5432\n",
+			wanted:  map[string]string{"zeta": "1", "db/host": "new.internal", "db/port": "5432"},
+			want:    "# app config\nzeta: 1\ndb:\n  # endpoint\n  host: \"new.internal\" # primary\n  port: 5432\n",
+			updated: []string{"db/host"},
+		},
+		{
+			name:         "new keys go in sorted position with their description",
+			yaml:         "db:\n  host: h\n  user: u\n",
+			wanted:       map[string]string{"db/host": "h", "db/port": "5432", "db/user": "u"},
+			descriptions: map[string]string{"/app/db/port": "PostgreSQL port"},
+			want:         "db:\n  host: h\n  # PostgreSQL port\n  port: 5432\n  user: u\n",
+			added:        []string{"db/port"},
+		},
+		{
+			name:   "stale keys are kept without prune",
+			yaml:   "a: 1\nold: 2\n",
+			wanted: map[string]string{"a": "1"},
+			want:   "a: 1\nold: 2\n",
+			stale:  []string{"old"},
+		},
+		{
+			name:    "prune removes stale keys and emptied containers",
+			yaml:    "a: 1\nold:\n  x: 2\n",
+			wanted:  map[string]string{"a": "1"},
+			prune:   true,
+			want:    "a: 1\n",
+			removed: []string{"old/x"},
+		},
+		{
+			name:    "a merged value is overridden locally, the anchor is untouched",
+			yaml:    "base: &base\n  token: t\n  host: h\nchild:\n  <<: *base\n",
+			wanted:  map[string]string{"base/token": "t", "base/host": "h", "child/token": "t", "child/host": "other"},
+			want:    "base: &base\n  token: t\n  host: h\nchild:\n  <<: *base\n  host: other\n",
+			updated: []string{"child/host"},
+		},
+		{
+			name:    "an aliased value is replaced by a local copy",
+			yaml:    "base: &base\n  token: t\ncopy: *base\n",
+			wanted:  map[string]string{"base/token": "t", "copy/token": "changed"},
+			want:    "base: &base\n  token: t\ncopy:\n  token: changed\n",
+			updated: []string{"copy/token"},
+		},
+		{
+			name:    "lists are updated by index",
+			yaml:    "hosts:\n  - a\n  - b\n",
+			wanted:  map[string]string{"hosts/0": "a", "hosts/1": "c", "hosts/2": "d"},
+			want:    "hosts:\n  - a\n  - c\n  - d\n",
+			updated: []string{"hosts/1"},
+			added:   []string{"hosts/2"},
+		},
+		{
+			name:    "values with surrounding whitespace round trip",
+			yaml:    "port: 5432\nflag: true\nname: x\n",
+			wanted:  map[string]string{"port": "5432\n", "flag": " true", "name": "x "},
+			want:    "port: |\n  5432\nflag: ' true'\nname: 'x '\n",
+			updated: []string{"flag", "name", "port"},
+		},
+		{
+			name:   "four space indentation is kept",
+			yaml:   "db:\n    host: h\n",
+			wanted: map[string]string{"db/host": "h", "db/port": "1"},
+			want:   "db:\n    host: h\n    port: 1\n",
+			added:  []string{"db/port"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, result := mergeYAML(t, tt.yaml, tt.wanted, tt.descriptions, tt.prune)
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+			for _, c := range []struct {
+				name      string
+				got, want []string
+			}{
+				{"updated", result.updated, tt.updated},
+				{"added", result.added, tt.added},
+				{"removed", result.removed, tt.removed},
+				{"stale", result.stale, tt.stale},
+			} {
+				if len(c.got) != 0 || len(c.want) != 0 {
+					if !reflect.DeepEqual(c.got, c.want) {
+						t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+					}
+				}
+			}
+
+			// Reading the merged document back gives exactly the wanted values
+			leaves, err := yamlLeaves([]byte(got), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			values := make(map[string]string)
+			for _, leaf := range leaves {
+				values[leaf.Path[1:]] = leaf.Value
+			}
+			if !tt.prune && len(tt.stale) > 0 {
+				return
+			}
+			if !reflect.DeepEqual(values, tt.wanted) {
+				t.Errorf("read back %v, want %v", values, tt.wanted)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var outputMode string
+
+// Event results reported in --output json mode
+const (
+	resultOK       = "ok"
+	resultPlanned  = "planned"
+	resultSkipped  = "skipped"
+	resultNotFound = "not_found"
+	resultError    = "error"
+	resultAborted  = "aborted"
+)
+
+// event is one JSON line written to stdout in --output json mode
+type event struct {
+	Operation string      `json:"operation"`
+	Path      string      `json:"path,omitempty"`
+	Type      string      `json:"type,omitempty"`
+	Result    string      `json:"result"`
+	ErrorCode string      `json:"error_code,omitempty"`
+	Message   string      `json:"message,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+func validateOutputMode() error {
+	switch outputMode {
+	case "text", "json":
+		return nil
+	}
+	return fmt.Errorf("unsupported --output %q (use text or json)", outputMode)
+}
+
+func jsonOutput() bool {
+	return outputMode == "json"
+}
+
+// emit writes e as a JSON line when --output json is active and reports whether it
+// did, so callers can fall back to their human output:
+//
+//	if !emit(event{...}) {
+//		fmt.Printf(...)
+//	}
+func emit(e event) bool {
+	if !jsonOutput() {
+		return false
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return true
+	}
+	fmt.Fprintln(os.Stdout, string(data))
+	return true
+}
+
+// emitError reports a failed operation on path, either as a JSON event or as the
+// usual "❌ Failed to ..." line on stderr
+func emitError(operation, verb, path string, err error) {
+	if emit(event{Operation: operation, Path: path, Result: resultError, ErrorCode: errorCode(err), Message: extractMessage(err)}) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s Failed to %s %s: %v\n", icon(markFail), verb, style(roleName).Sprint(path), style(roleError).Sprint(extractMessage(err)))
+}
+
+// abort reports that the user declined to go ahead with operation
+func abort(operation string) {
+	if !emit(event{Operation: operation, Result: resultAborted}) {
+		fmt.Println("Aborted.")
+	}
+}
+
+// humanOut is where informational text goes: stdout normally, stderr in json mode
+// so stdout stays machine readable
+func humanOut() io.Writer {
+	if jsonOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// confirm asks a yes/no question (on stderr in json mode); def is returned on empty input
+func confirm(prompt string, def bool) bool {
+	fmt.Fprint(humanOut(), prompt)
+	reader := bufio.NewReader(os.Stdin)
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(strings.ToLower(input))
+	if input == "" {
+		return def
+	}
+	return input == "y" || input == "yes"
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestEmit(t *testing.T) {
+	apiErr := &smithy.GenericAPIError{Code: "ParameterNotFound", Message: "no such key"}
+	tests := []struct {
+		name string
+		mode string
+		fn   func()
+		want string
+	}{
+		{"text mode prints nothing", "text", func() { emit(event{Operation: "get", Result: resultOK}) }, ""},
+		{"event", "json", func() {
+			emit(event{Operation: "set", Path: "/app/x", Type: "String", Result: resultOK, Data: map[string]int{"version": 2}})
+		}, `{"operation":"set","path":"/app/x","type":"String","result":"ok","data":{"version":2}}` + "\n"},
+		{"api error", "json", func() { emitError("rm", "delete", "/app/x", apiErr) },
+			`{"operation":"rm","path":"/app/x","result":"error","error_code":"ParameterNotFound","message":"[ParameterNotFound] no such key"}` + "\n"},
+		{"plain error", "json", func() { emitError("rm", "delete", "/app/x", errors.New("boom")) },
+			`{"operation":"rm","path":"/app/x","result":"error","message":"boom"}` + "\n"},
+		{"abort", "json", func() { abort("delete") }, `{"operation":"delete","result":"aborted"}` + "\n"},
+	}
+	defer func(m string) { outputMode = m }(outputMode)
+	for _, tt := range tests {
+		outputMode = tt.mode
+		if got := captureStdout(t, tt.fn); got != tt.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeleteEvents(t *testing.T) {
+	_, client := newFakeSSM(t, map[string]string{"/app/a": "1", "/app/b": "2"})
+	defer func(m string) { outputMode = m }(outputMode)
+	outputMode = "json"
+
+	got := captureStdout(t, func() { deleteInBatches([]string{"/app/a", "/app/b", "/app/gone"}, client) })
+	want := []string{
+		`{"operation":"delete","path":"/app/a","result":"ok"}`,
+		`{"operation":"delete","path":"/app/b","result":"ok"}`,
+		`{"operation":"delete","path":"/app/gone","result":"not_found"}`,
+	}
+	if lines := strings.Split(strings.TrimSpace(got), "\n"); strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
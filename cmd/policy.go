@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Parameter policy types, as used in the policy JSON
+const (
+	policyExpiration             = "Expiration"
+	policyExpirationNotification = "ExpirationNotification"
+	policyNoChangeNotification   = "NoChangeNotification"
+)
+
+// policyRule attaches parameter policies to the leaves matching Glob (relative to
+// the load prefix). Expiration is a duration from now (24h, 7d) or an RFC 3339
+// timestamp, the notifications are durations in whole hours or days.
+type policyRule struct {
+	Glob                   string `yaml:"-"`
+	Expiration             string `yaml:"expiration"`
+	ExpirationNotification string `yaml:"expiration_notification"`
+	NoChangeNotification   string `yaml:"no_change_notification"`
+}
+
+// yamlPolicyRules reads the "policies" section of the x-aws-ssm metadata block,
+// keeping the order of the file so later rules win
+func yamlPolicyRules(raw []byte) ([]policyRule, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	meta := mappingValue(documentRoot(&doc), yamlMetaKey)
+	policies := mappingValue(meta, "policies")
+	if policies == nil {
+		return nil, nil
+	}
+	if policies.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: %s.policies must map paths to policies", policies.Line, yamlMetaKey)
+	}
+
+	var rules []policyRule
+	for i := 0; i+1 < len(policies.Content); i += 2 {
+		key, value := policies.Content[i], policies.Content[i+1]
+		var r policyRule
+		if err := value.Decode(&r); err != nil {
+			return nil, fmt.Errorf("line %d: %w", value.Line, err)
+		}
+		r.Glob = key.Value
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// flagPolicyRules turns --expiration, --expiration-notification and
+// --no-change-notification (glob=value) into rules
+func flagPolicyRules() []policyRule {
+	var rules []policyRule
+	for _, glob := range sortedKeys(loadExpiration) {
+		rules = append(rules, policyRule{Glob: glob, Expiration: loadExpiration[glob]})
+	}
+	for _, glob := range sortedKeys(loadExpirationNotification) {
+		rules = append(rules, policyRule{Glob: glob, ExpirationNotification: loadExpirationNotification[glob]})
+	}
+	for _, glob := range sortedKeys(loadNoChangeNotification) {
+		rules = append(rules, policyRule{Glob: glob, NoChangeNotification: loadNoChangeNotification[glob]})
+	}
+	return rules
+}
+
+// leafPolicies resolves the policies of every leaf into the JSON accepted by
+// PutParameter, keyed by path. Leaves without policies are left out.
+func leafPolicies(leaves []yamlLeaf, root string, rules []policyRule) (map[string]string, error) {
+	result := make(map[string]string)
+	if len(rules) == 0 {
+		return result, nil
+	}
+	now := time.Now()
+	for _, leaf := range leaves {
+		var merged policyRule
+		for _, r := range rules {
+			if !globMatch(r.Glob, strings.TrimPrefix(leaf.Path, root)) {
+				continue
+			}
+			if r.Expiration != "" {
+				merged.Expiration = r.Expiration
+			}
+			if r.ExpirationNotification != "" {
+				merged.ExpirationNotification = r.ExpirationNotification
+			}
+			if r.NoChangeNotification != "" {
+				merged.NoChangeNotification = r.NoChangeNotification
+			}
+		}
+		policies, err := merged.policyJSON(now)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", leaf.Path, err)
+		}
+		if policies != "" {
+			result[leaf.Path] = policies
+		}
+	}
+	return result, nil
+}
+
+type policyDocument struct {
+	Type       string            `json:"Type"`
+	Version    string            `json:"Version"`
+	Attributes map[string]string `json:"Attributes"`
+}
+
+func (r policyRule) policyJSON(now time.Time) (string, error) {
+	var docs []policyDocument
+	if r.Expiration != "" {
+		at, err := time.Parse(time.RFC3339, r.Expiration)
+		if err != nil {
+			d, ageErr := parseAge(r.Expiration)
+			if ageErr != nil {
+				return "", fmt.Errorf("invalid expiration %q (use a duration like 24h or an RFC 3339 timestamp)", r.Expiration)
+			}
+			at = now.Add(d)
+		}
+		if !at.After(now) {
+			return "", fmt.Errorf("expiration %s is in the past", at.UTC().Format(time.RFC3339))
+		}
+		docs = append(docs, policyDocument{Type: policyExpiration, Version: "1.0", Attributes: map[string]string{
+			"Timestamp": at.UTC().Format("2006-01-02T15:04:05.000Z"),
+		}})
+	}
+	if r.ExpirationNotification != "" {
+		n, unit, err := policyUnits(r.ExpirationNotification)
+		if err != nil {
+			return "", fmt.Errorf("expiration notification: %w", err)
+		}
+		docs = append(docs, policyDocument{Type: policyExpirationNotification, Version: "1.0", Attributes: map[string]string{"Before": n, "Unit": unit}})
+	}
+	if r.NoChangeNotification != "" {
+		n, unit, err := policyUnits(r.NoChangeNotification)
+		if err != nil {
+			return "", fmt.Errorf("no-change notification: %w", err)
+		}
+		docs = append(docs, policyDocument{Type: policyNoChangeNotification, Version: "1.0", Attributes: map[string]string{"After": n, "Unit": unit}})
+	}
+	if len(docs) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(docs)
+	return string(data), err
+}
+
+// policyUnits converts a duration to the whole Days or Hours notifications use
+func policyUnits(s string) (string, string, error) {
+	d, err := parseAge(s)
+	if err != nil {
+		return "", "", err
+	}
+	switch {
+	case d <= 0:
+		return "", "", fmt.Errorf("duration %q must be positive", s)
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprint(int64(d / (24 * time.Hour))), "Days", nil
+	case d%time.Hour == 0:
+		return fmt.Sprint(int64(d / time.Hour)), "Hours", nil
+	}
+	return "", "", fmt.Errorf("duration %q must be whole hours or days", s)
+}
+
+// dropPoliciesWithoutAdvanced warns about policies and drops them unless --tier
+// is advanced or intelligent-tiering. Parameter Store only accepts policies on
+// Advanced parameters, which are billed and can't be turned back into Standard
+// ones, so --tier auto doesn't promote a key just because it has a policy.
+func dropPoliciesWithoutAdvanced(policies map[string]string) {
+	if advancedTierAllowed() {
+		return
+	}
+	for _, path := range sortedKeys(policies) {
+		delete(policies, path)
+		msg := fmt.Sprintf("policies need the Advanced tier, ignored with --tier %s (use --tier advanced)", loadTier)
+		if emit(event{Operation: "load", Path: path, Result: resultSkipped, Message: msg}) {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s %s: %s\n", icon(markWarn), style(roleName).Sprint(path), style(roleWarning).Sprint(msg))
+	}
+}
+
+// describePolicy renders an inline policy for ls and tree, e.g. "expires 2025-01-31T00:00:00.000Z"
+func describePolicy(p types.ParameterInlinePolicy) string {
+	var doc policyDocument
+	if err := json.Unmarshal([]byte(aws.ToString(p.PolicyText)), &doc); err != nil {
+		return aws.ToString(p.PolicyType)
+	}
+	a := doc.Attributes
+	var text string
+	switch doc.Type {
+	case policyExpiration:
+		text = "expires " + a["Timestamp"]
+	case policyExpirationNotification:
+		text = fmt.Sprintf("notify %s %s before expiry", a["Before"], strings.ToLower(a["Unit"]))
+	case policyNoChangeNotification:
+		text = fmt.Sprintf("notify after %s %s unchanged", a["After"], strings.ToLower(a["Unit"]))
+	default:
+		text = doc.Type
+	}
+	if status := aws.ToString(p.PolicyStatus); status != "" && status != "Pending" {
+		text += " (" + strings.ToLower(status) + ")"
+	}
+	return text
+}
+
+func describePolicies(policies []types.ParameterInlinePolicy) []string {
+	var texts []string
+	for _, p := range policies {
+		texts = append(texts, describePolicy(p))
+	}
+	return texts
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestPolicyUnits(t *testing.T) {
+	tests := []struct {
+		in        string
+		n, unit   string
+		wantError bool
+	}{
+		{"4h", "4", "Hours", false},
+		{"48h", "2", "Days", false},
+		{"30d", "30", "Days", false},
+		{"2w", "14", "Days", false},
+		{"90m", "", "", true},
+		{"0h", "", "", true},
+		{"-2d", "", "", true},
+		{"soon", "", "", true},
+	}
+	for _, tt := range tests {
+		n, unit, err := policyUnits(tt.in)
+		if (err != nil) != tt.wantError {
+			t.Errorf("policyUnits(%q) error = %v, want error %v", tt.in, err, tt.wantError)
+			continue
+		}
+		if n != tt.n || unit != tt.unit {
+			t.Errorf("policyUnits(%q) = %s %s, want %s %s", tt.in, n, unit, tt.n, tt.unit)
+		}
+	}
+}
+
+func TestPolicyJSON(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		rule      policyRule
+		want      string
+		wantError bool
+	}{
+		{"none", policyRule{}, "", false},
+		{
+			"expiration from now",
+			policyRule{Expiration: "24h"},
+			`[{"Type":"Expiration","Version":"1.0","Attributes":{"Timestamp":"2025-01-02T00:00:00.000Z"}}]`,
+			false,
+		},
+		{
+			"expiration timestamp",
+			policyRule{Expiration: "2025-01-31T12:00:00+02:00"},
+			`[{"Type":"Expiration","Version":"1.0","Attributes":{"Timestamp":"2025-01-31T10:00:00.000Z"}}]`,
+			false,
+		},
+		{
+			"all policies",
+			policyRule{Expiration: "7d", ExpirationNotification: "4h", NoChangeNotification: "30d"},
+			`[{"Type":"Expiration","Version":"1.0","Attributes":{"Timestamp":"2025-01-08T00:00:00.000Z"}},` +
+				`{"Type":"ExpirationNotification","Version":"1.0","Attributes":{"Before":"4","Unit":"Hours"}},` +
+				`{"Type":"NoChangeNotification","Version":"1.0","Attributes":{"After":"30","Unit":"Days"}}]`,
+			false,
+		},
+		{"expiration in the past", policyRule{Expiration: "2024-12-31T00:00:00Z"}, "", true},
+		{"invalid expiration", policyRule{Expiration: "tomorrow"}, "", true},
+		{"partial hours", policyRule{NoChangeNotification: "90m"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := tt.rule.policyJSON(now)
+		if (err != nil) != tt.wantError {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantError)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLeafPolicies(t *testing.T) {
+	leaves := []yamlLeaf{
+		{Path: "/app/db/session_token"},
+		{Path: "/app/db/host"},
+		{Path: "/app/api/temp_key"},
+	}
+	rules := []policyRule{
+		{Glob: "db/*", NoChangeNotification: "30d"},
+		{Glob: "**/temp_*", NoChangeNotification: "1d"},
+		{Glob: "session_token", ExpirationNotification: "4h"},
+	}
+	got, err := leafPolicies(leaves, "/app", rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"/app/db/session_token": `[{"Type":"ExpirationNotification","Version":"1.0","Attributes":{"Before":"4","Unit":"Hours"}},` +
+			`{"Type":"NoChangeNotification","Version":"1.0","Attributes":{"After":"30","Unit":"Days"}}]`,
+		"/app/db/host":      `[{"Type":"NoChangeNotification","Version":"1.0","Attributes":{"After":"30","Unit":"Days"}}]`,
+		"/app/api/temp_key": `[{"Type":"NoChangeNotification","Version":"1.0","Attributes":{"After":"1","Unit":"Days"}}]`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d leaves with policies, want %d: %v", len(got), len(want), got)
+	}
+	for path, w := range want {
+		if got[path] != w {
+			t.Errorf("%s: got %s\nwant %s", path, got[path], w)
+		}
+	}
+}
+
+func TestValueTier(t *testing.T) {
+	defer func(tier string) { loadTier = tier }(loadTier)
+	large := string(make([]byte, maxStandardValue+1))
+	tests := []struct {
+		tier     string
+		value    string
+		want     types.ParameterTier
+		policies bool
+	}{
+		{tierAuto, "small", "", false},
+		{tierAuto, large, types.ParameterTierAdvanced, false},
+		{"standard", "small", types.ParameterTierStandard, false},
+		{"advanced", "small", types.ParameterTierAdvanced, true},
+		{"intelligent-tiering", "small", types.ParameterTierIntelligentTiering, true},
+	}
+	for _, tt := range tests {
+		loadTier = tt.tier
+		if got := valueTier(tt.value); got != tt.want {
+			t.Errorf("valueTier(%s, %d bytes) = %q, want %q", tt.tier, len(tt.value), got, tt.want)
+		}
+		if got := advancedTierAllowed(); got != tt.policies {
+			t.Errorf("advancedTierAllowed() with --tier %s = %v, want %v", tt.tier, got, tt.policies)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Presentation settings shared by every command: when to use colors, whether to
+// print emoji, and which colors the theme gives to each role
+var (
+	colorMode string
+	noEmoji   bool
+	themeSpec string
+)
+
+type themeRole string
+
+const (
+	roleName     themeRole = "name"     // parameter names in status and error lines
+	roleItem     themeRole = "item"     // keys listed before a confirmation
+	roleMuted    themeRole = "muted"    // values, versions, hints
+	roleString   themeRole = "string"   // String parameters
+	roleSecure   themeRole = "secure"   // SecureString parameters
+	roleIndex    themeRole = "index"    // list indices in trees
+	roleSuccess  themeRole = "success"  // additions, present cells
+	roleWarning  themeRole = "warning"  // changes, differences
+	roleError    themeRole = "error"    // failures, removals, missing cells
+	roleHeading  themeRole = "heading"  // table headers and labels
+	roleMatch    themeRole = "match"    // search hits
+	roleRoot     themeRole = "root"     // the yaml-tree root
+	roleBranch   themeRole = "branch"   // branches in browse
+	roleSelected themeRole = "selected" // the cursor in browse
+	rolePrompt   themeRole = "prompt"   // questions in browse
+)
+
+var theme = map[themeRole][]color.Attribute{
+	roleName:     {color.FgWhite, color.Bold},
+	roleItem:     {color.FgHiBlack, color.Bold},
+	roleMuted:    {color.FgHiBlack},
+	roleString:   {color.FgWhite},
+	roleSecure:   {color.FgCyan},
+	roleIndex:    {color.FgYellow},
+	roleSuccess:  {color.FgGreen},
+	roleWarning:  {color.FgYellow},
+	roleError:    {color.FgRed},
+	roleHeading:  {color.Bold},
+	roleMatch:    {color.FgHiYellow, color.Bold, color.Underline},
+	roleRoot:     {color.FgHiCyan, color.Bold},
+	roleBranch:   {color.FgHiBlue, color.Bold},
+	roleSelected: {color.ReverseVideo},
+	rolePrompt:   {color.FgYellow, color.Bold},
+}
+
+var themeAttributes = map[string]color.Attribute{
+	"black":     color.FgBlack,
+	"red":       color.FgRed,
+	"green":     color.FgGreen,
+	"yellow":    color.FgYellow,
+	"blue":      color.FgBlue,
+	"magenta":   color.FgMagenta,
+	"cyan":      color.FgCyan,
+	"white":     color.FgWhite,
+	"hiblack":   color.FgHiBlack,
+	"hired":     color.FgHiRed,
+	"higreen":   color.FgHiGreen,
+	"hiyellow":  color.FgHiYellow,
+	"hiblue":    color.FgHiBlue,
+	"himagenta": color.FgHiMagenta,
+	"hicyan":    color.FgHiCyan,
+	"hiwhite":   color.FgHiWhite,
+	"bold":      color.Bold,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+	"reverse":   color.ReverseVideo,
+}
+
+// style returns the themed color for role
+func style(role themeRole) *color.Color {
+	return color.New(theme[role]...)
+}
+
+type mark int
+
+const (
+	markOK mark = iota
+	markFail
+	markWarn
+	markSecure
+	markBackup
+	markTrash
+	markRestored
+)
+
+// Emoji that render narrower than their width carry a trailing space, as before
+var emojiMarks = map[mark]string{
+	markOK:       "✅",
+	markFail:     "❌",
+	markWarn:     "⚠️ ",
+	markSecure:   "🔒",
+	markBackup:   "💾",
+	markTrash:    "🗑️ ",
+	markRestored: "♻️ ",
+}
+
+var textMarks = map[mark]string{
+	markOK:       "[ok]",
+	markFail:     "[fail]",
+	markWarn:     "[warn]",
+	markSecure:   "[secure]",
+	markBackup:   "[backup]",
+	markTrash:    "[trash]",
+	markRestored: "[restored]",
+}
+
+// icon returns the emoji for m, or its textual marker with --no-emoji
+func icon(m mark) string {
+	if noEmoji {
+		return textMarks[m]
+	}
+	return emojiMarks[m]
+}
+
+// applyPresentation resolves --color, NO_COLOR, the terminal check and the theme
+func applyPresentation() error {
+	if err := applyTheme(themeSpec); err != nil {
+		return err
+	}
+
+	switch colorMode {
+	case "always":
+		color.NoColor = false
+	case "never":
+		color.NoColor = true
+	case "auto":
+		color.NoColor = !autoColor(isTerminal(os.Stdout))
+	default:
+		return fmt.Errorf("unsupported --color %q (use auto, always or never)", colorMode)
+	}
+	return nil
+}
+
+// autoColor decides --color auto: a non-empty NO_COLOR (https://no-color.org) wins,
+// otherwise only real terminals get colors
+func autoColor(tty bool) bool {
+	return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && tty
+}
+
+// applyTheme overrides theme roles from a spec like "secure=magenta,muted=blue+faint"
+func applyTheme(spec string) error {
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		role, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid theme entry %q (expected role=color)", entry)
+		}
+		role = strings.ToLower(strings.TrimSpace(role))
+		if _, known := theme[themeRole(role)]; !known {
+			return fmt.Errorf("unknown theme role %q (known: %s)", role, strings.Join(themeRoles(), ", "))
+		}
+
+		var attrs []color.Attribute
+		for _, name := range strings.Split(value, "+") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "none" || name == "" {
+				continue
+			}
+			attr, known := themeAttributes[name]
+			if !known {
+				return fmt.Errorf("unknown color %q in theme entry %q", name, entry)
+			}
+			attrs = append(attrs, attr)
+		}
+		theme[themeRole(role)] = attrs
+	}
+	return nil
+}
+
+func themeRoles() []string {
+	roles := make([]string, 0, len(theme))
+	for r := range theme {
+		roles = append(roles, string(r))
+	}
+	sort.Strings(roles)
+	return roles
+}
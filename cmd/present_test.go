@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestAutoColor(t *testing.T) {
+	tests := []struct {
+		noColor, term string
+		tty           bool
+		want          bool
+	}{
+		{"", "xterm", true, true},
+		{"", "xterm", false, false},
+		{"1", "xterm", true, false},
+		{"false", "xterm", true, false},
+		{"", "dumb", true, false},
+	}
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		t.Setenv("TERM", tt.term)
+		if got := autoColor(tt.tty); got != tt.want {
+			t.Errorf("NO_COLOR=%q TERM=%s tty=%v: autoColor = %v, want %v", tt.noColor, tt.term, tt.tty, got, tt.want)
+		}
+	}
+}
+
+func TestApplyPresentation(t *testing.T) {
+	defer func(m string, n bool) { colorMode, color.NoColor = m, n }(colorMode, color.NoColor)
+	defer func(s string) { themeSpec = s }(themeSpec)
+	themeSpec = ""
+	tests := []struct {
+		mode        string
+		wantNoColor bool
+		wantErr     bool
+	}{
+		{"always", false, false},
+		{"never", true, false},
+		{"auto", true, false}, // stdout is not a terminal under go test
+		{"sometimes", false, true},
+	}
+	for _, tt := range tests {
+		colorMode = tt.mode
+		err := applyPresentation()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("--color %s: error = %v, want error %v", tt.mode, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && color.NoColor != tt.wantNoColor {
+			t.Errorf("--color %s: NoColor = %v, want %v", tt.mode, color.NoColor, tt.wantNoColor)
+		}
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	tests := []struct {
+		spec    string
+		role    themeRole
+		want    []color.Attribute
+		wantErr bool
+	}{
+		{"secure=magenta", roleSecure, []color.Attribute{color.FgMagenta}, false},
+		{" Muted = hiblue+Faint , ", roleMuted, []color.Attribute{color.FgHiBlue, color.Faint}, false},
+		{"match=none", roleMatch, nil, false},
+		{"secure", "", nil, true},
+		{"sparkle=red", "", nil, true},
+		{"secure=plaid", "", nil, true},
+	}
+	for _, tt := range tests {
+		saved := make(map[themeRole][]color.Attribute, len(theme))
+		for k, v := range theme {
+			saved[k] = v
+		}
+		err := applyTheme(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("applyTheme(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+		} else if !tt.wantErr && !reflect.DeepEqual(theme[tt.role], tt.want) {
+			t.Errorf("applyTheme(%q): %s = %v, want %v", tt.spec, tt.role, theme[tt.role], tt.want)
+		}
+		theme = saved
+	}
+}
+
+func TestIcon(t *testing.T) {
+	defer func(n bool) { noEmoji = n }(noEmoji)
+	tests := []struct {
+		noEmoji bool
+		mark    mark
+		want    string
+	}{
+		{false, markSecure, "🔒"},
+		{true, markSecure, "[secure]"},
+		{false, markWarn, "⚠️ "},
+		{true, markFail, "[fail]"},
+	}
+	for _, tt := range tests {
+		noEmoji = tt.noEmoji
+		if got := icon(tt.mark); got != tt.want {
+			t.Errorf("icon(%d) with no-emoji=%v = %q, want %q", tt.mark, tt.noEmoji, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	purgeOlderThan string
+	purgeYes       bool
+)
+
+var purgeCmd = &cobra.Command{
+	Use:     "purge",
+	Short:   "Permanently remove old entries from the trash prefix",
+	Aliases: []string{"pu"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if purgeOlderThan == "" {
+			return fmt.Errorf("--older-than is required (use 0d to purge everything)")
+		}
+		age, err := parseAge(purgeOlderThan)
+		if err != nil {
+			return err
+		}
+		cutoff := time.Now().Add(-age)
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		entries, err := listTrash(client)
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		for _, e := range entries {
+			if e.Stamp.Before(cutoff) {
+				names = append(names, e.Name)
+			}
+		}
+		if len(names) == 0 {
+			fmt.Fprintf(humanOut(), "No trash entries older than %s.\n", purgeOlderThan)
+			return nil
+		}
+
+		fmt.Fprintf(humanOut(), "The following %d trash entries will be permanently deleted:\n", len(names))
+		for _, name := range names {
+			if !emit(event{Operation: "delete", Path: name, Result: resultPlanned}) {
+				fmt.Println(style(roleItem).Sprint(name))
+			}
+		}
+
+		if !purgeYes && !confirmDelete(normalizedTrashPrefix(), len(names)) {
+			abort("purge")
+			return nil
+		}
+
+		deleteInBatches(names, client)
+		return nil
+	},
+}
+
+func init() {
+	purgeCmd.Flags().StringVar(&purgeOlderThan, "older-than", "", "Purge entries trashed longer ago than this (e.g. 30d, 2w, 12h) (required)")
+	purgeCmd.Flags().BoolVarP(&purgeYes, "yes", "y", false, "Skip confirmation prompt")
+	purgeCmd.Flags().IntVar(&deleteConfirmThreshold, "confirm-threshold", 10, "Require typing the trash prefix to confirm when more than this many keys are affected")
+	purgeCmd.Flags().StringVar(&trashPrefix, "trash-prefix", defaultTrashPrefix, "SSM prefix holding soft-deleted parameters")
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreList    bool
+	restoreLatest  bool
+	restoreYes     bool
+	restoreDryRun  bool
+	restoreInclude []string
+	restoreExclude []string
+)
+
+var restoreCmd = &cobra.Command{
+	Use:     "restore [snapshot]",
+	Short:   "Restore parameters from a backup snapshot taken before delete or load --overwrite",
+	Aliases: []string{"re", "rs"},
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := resolveBackupDir()
+
+		if restoreList {
+			return listSnapshots(dir)
+		}
+
+		var file string
+		switch {
+		case len(args) == 1:
+			file = args[0]
+		case restoreLatest:
+			files, err := snapshotFiles(dir)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no snapshots found in %s", dir)
+			}
+			file = files[len(files)-1]
+		default:
+			return fmt.Errorf("a snapshot file or --latest is required (see --list)")
+		}
+
+		snap, err := readSnapshot(file)
+		if err != nil {
+			return err
+		}
+
+		var params []snapshotParam
+		for _, p := range snap.Parameters {
+			if matchesFilters(p.Name, restoreInclude, restoreExclude) {
+				params = append(params, p)
+			}
+		}
+		if len(params) == 0 {
+			fmt.Fprintln(humanOut(), "No parameters in the snapshot match the filters.")
+			return nil
+		}
+
+		fmt.Fprintf(humanOut(), "Snapshot %s (%s, %s)\n", filepath.Base(file), snap.Operation, snap.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+		fmt.Fprintf(humanOut(), "The following %d parameters will be restored:\n", len(params))
+		for _, p := range params {
+			if emit(event{Operation: "restore", Path: p.Name, Type: p.Type, Result: resultPlanned}) {
+				continue
+			}
+			lockIcon := ""
+			if p.Type == string(types.ParameterTypeSecureString) {
+				lockIcon = " " + icon(markSecure)
+			}
+			fmt.Printf("%s%s\n", style(roleItem).Sprint(p.Name), lockIcon)
+		}
+
+		if restoreDryRun {
+			return nil
+		}
+
+		if !restoreYes && !confirm("Existing values will be overwritten. Continue? (y/N): ", false) {
+			abort("restore")
+			return nil
+		}
+
+		key, err := snapshotKey(file, params)
+		if err != nil {
+			return err
+		}
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+		if snap.Region != "" && snap.Region != client.Options().Region {
+			fmt.Fprintf(os.Stderr, "%s Snapshot was taken in %s, restoring into %s\n", icon(markWarn), snap.Region, client.Options().Region)
+		}
+
+		restored := 0
+		for _, p := range params {
+			if err := putSnapshotParam(p, key, true, client); err != nil {
+				emitError("restore", "restore", p.Name, err)
+				continue
+			}
+			restored++
+			if !emit(event{Operation: "restore", Path: p.Name, Type: p.Type, Result: resultOK}) {
+				fmt.Printf("%s Restored %s\n", icon(markOK), p.Name)
+			}
+		}
+		fmt.Fprintf(humanOut(), "\nRestored %d of %d parameters\n", restored, len(params))
+		return nil
+	},
+}
+
+func init() {
+	restoreCmd.Flags().BoolVarP(&restoreList, "list", "l", false, "List available snapshots")
+	restoreCmd.Flags().BoolVar(&restoreLatest, "latest", false, "Restore the most recent snapshot")
+	restoreCmd.Flags().BoolVarP(&restoreYes, "yes", "y", false, "Skip confirmation prompt")
+	restoreCmd.Flags().BoolVarP(&restoreDryRun, "dry-run", "n", false, "Only show what would be restored")
+	restoreCmd.Flags().StringSliceVarP(&restoreInclude, "include", "i", nil, "Only restore parameters matching these globs")
+	restoreCmd.Flags().StringSliceVarP(&restoreExclude, "exclude", "x", nil, "Skip parameters matching these globs")
+}
+
+// snapshotFiles returns the snapshot files in dir, oldest first
+func snapshotFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func listSnapshots(dir string) error {
+	files, err := snapshotFiles(dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Printf("No snapshots found in %s\n", dir)
+		return nil
+	}
+	for _, file := range files {
+		snap, err := readSnapshot(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", icon(markWarn), err)
+			continue
+		}
+		fmt.Printf("%s  %-8s %4d params  %s\n",
+			snap.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			snap.Operation,
+			len(snap.Parameters),
+			style(roleMuted).Sprint(file))
+	}
+	return nil
+}
+
+// snapshotKey finds the key that decrypts the sealed values of params: the backup
+// key, or for snapshots taken before it moved out of the backup directory, a
+// backup.key next to the snapshot. It returns nil when nothing is sealed.
+func snapshotKey(file string, params []snapshotParam) ([]byte, error) {
+	var sealed string
+	for _, p := range params {
+		if p.EncryptedValue != "" {
+			sealed = p.EncryptedValue
+			break
+		}
+	}
+	if sealed == "" {
+		return nil, nil
+	}
+
+	var firstErr error
+	for _, candidate := range []string{backupKeyPath(), filepath.Join(filepath.Dir(file), backupKeyFile)} {
+		key, err := loadBackupKey(candidate, false)
+		if err == nil {
+			if _, err = openValue(key, sealed); err == nil {
+				return key, nil
+			}
+			err = fmt.Errorf("backup key %s doesn't decrypt this snapshot", candidate)
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+)
+
+var rmYes bool
+
+var rmCmd = &cobra.Command{
+	Use:   "rm <name>...",
+	Short: "Delete individual parameters by name",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		lookups := lookupParameters(args, client)
+
+		var names []string
+		for _, name := range args {
+			l := lookups[name]
+			switch l.status {
+			case paramNotFound:
+				if emit(event{Operation: "delete", Path: name, Result: resultNotFound}) {
+					continue
+				}
+				fmt.Fprintf(os.Stderr, "%s Not found %s\n", icon(markWarn), style(roleName).Sprint(name))
+				continue
+			case paramAccessDenied, paramLookupFailed:
+				if emit(event{Operation: "delete", Path: name, Result: resultError, Message: l.message}) {
+					continue
+				}
+				fmt.Fprintf(os.Stderr, "%s Failed to look up %s: %v\n", icon(markFail), style(roleName).Sprint(name), style(roleError).Sprint(l.message))
+				continue
+			}
+			names = append(names, name)
+			if emit(plannedDelete(name, l)) {
+				continue
+			}
+			lockIcon := ""
+			if l.paramType == types.ParameterTypeSecureString {
+				lockIcon = " " + icon(markSecure)
+			}
+			fmt.Printf("%s%s\n", style(roleItem).Sprint(name), lockIcon)
+		}
+		if len(names) == 0 {
+			return nil
+		}
+
+		if err := checkTrashable(names); err != nil {
+			return err
+		}
+		if !rmYes {
+			prompt := fmt.Sprintf("Delete %d parameter(s)? (y/N): ", len(names))
+			if deleteSoft {
+				prompt = fmt.Sprintf("Move %d parameter(s) to %s? (y/N): ", len(names), normalizedTrashPrefix())
+			}
+			if !confirm(prompt, false) {
+				abort("delete")
+				return nil
+			}
+		}
+
+		return removeParameters(names, client)
+	},
+}
+
+func init() {
+	rmCmd.Flags().BoolVarP(&rmYes, "yes", "y", false, "Skip confirmation prompt")
+	rmCmd.Flags().BoolVar(&noBackup, "no-backup", false, "Don't write a backup snapshot before deleting")
+	rmCmd.Flags().BoolVarP(&deleteSoft, "soft", "S", false, "Move parameters into the trash prefix instead of deleting them")
+	rmCmd.Flags().StringVar(&trashPrefix, "trash-prefix", defaultTrashPrefix, "SSM prefix used by --soft")
+}
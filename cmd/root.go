@@ -4,7 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -27,6 +31,22 @@ var rootCmd = &cobra.Command{
 	SilenceErrors: true,
 	Short:         fmt.Sprintf("%s is a CLI tool for managing AWS SSM Params", Name),
 	Long:          fmt.Sprintf("%s is a CLI utility for managing YAML ↔ AWS SSM Parameter Store", Name),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputMode(); err != nil {
+			return err
+		}
+		if err := applyPresentation(); err != nil {
+			return err
+		}
+		if err := applyProjectConfig(cmd); err != nil {
+			return err
+		}
+		if jsonOutput() {
+			// Keep escape codes out of messages and anything printed to stderr
+			color.NoColor = true
+		}
+		return nil
+	},
 }
 
 func Execute(version string) {
@@ -36,7 +56,12 @@ func Execute(version string) {
 
 	rootCmd.Version = version
 
-	cobra.CheckErr(rootCmd.Execute())
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil && jsonOutput() {
+		emit(event{Operation: cmd.Name(), Result: resultError, ErrorCode: errorCode(err), Message: extractMessage(err)})
+		os.Exit(1)
+	}
+	cobra.CheckErr(err)
 
 	if debugFlag {
 		slog.SetLogLoggerLevel(slog.LevelDebug)
@@ -55,8 +80,45 @@ func init() {
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(treeCmd)
 	rootCmd.AddCommand(yamlTreeCmd)
+	rootCmd.AddCommand(matrixCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(undeleteCmd)
+	rootCmd.AddCommand(purgeCmd)
+	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(setCmd)
+	rootCmd.AddCommand(rmCmd)
+	rootCmd.AddCommand(lsCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(browseCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(validateCmd)
 	//rootCmd.AddCommand(versionCmd)
 
 	rootCmd.PersistentFlags().BoolVarP(&debugFlag, "debug", "b", false, "Enable debugging logging")
 	rootCmd.PersistentFlags().StringVarP(&awsRegion, "region", "r", "", "AWS region to use (overrides default profile)")
+	rootCmd.PersistentFlags().StringVar(&awsProfile, "profile", "", "AWS shared config profile to use")
+	rootCmd.PersistentFlags().StringVarP(&envName, "env", "e", "", "Named environment from .aws-ssm.yaml (profile, region, prefix, KMS key, tags)")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file to use instead of searching for .aws-ssm.yaml")
+	rootCmd.PersistentFlags().StringVarP(&outputMode, "output", "O", "text", "Output mode: text (human readable) or json (one JSON event per line)")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Use colors: auto (only on a terminal, honours NO_COLOR), always or never")
+	rootCmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", os.Getenv("AWS_SSM_NO_EMOJI") != "", "Print textual markers like [ok] and [secure] instead of emoji (or set AWS_SSM_NO_EMOJI)")
+	rootCmd.PersistentFlags().StringVar(&themeSpec, "theme", os.Getenv("AWS_SSM_THEME"), "Color overrides as role=color[+attr],... e.g. secure=magenta,muted=blue (or set AWS_SSM_THEME)")
+	rootCmd.PersistentFlags().StringVar(&backupDir, "backup-dir", "", fmt.Sprintf("Directory for backup snapshots (default %s)", defaultBackupDir()))
+}
+
+// newSSMClient loads the default AWS config (honouring --region and --profile) and returns an SSM client
+func newSSMClient() (*ssm.Client, error) {
+	var cfgOpts []func(*config.LoadOptions) error
+	if awsRegion != "" {
+		cfgOpts = append(cfgOpts, config.WithRegion(awsRegion))
+	}
+	if awsProfile != "" {
+		cfgOpts = append(cfgOpts, config.WithSharedConfigProfile(awsProfile))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, cfgOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return ssm.NewFromConfig(awsCfg), nil
 }
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Minimal SARIF 2.1.0 model, enough for GitHub code scanning uploads
+
+// URI base IDs of the report: files are relative to the repository root, and
+// parameters get a synthetic artifact named after their path
+const (
+	sarifSourceRoot = "%SRCROOT%"
+	sarifSSMRoot    = "SSM"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	Help             sarifMessage `json:"help"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI         string        `json:"uri,omitempty"`
+	URIBaseID   string        `json:"uriBaseId,omitempty"`
+	Description *sarifMessage `json:"description,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func sarifReport(findings []scanFinding) sarifLog {
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		loc := sarifLocation{
+			PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifact(f)},
+			LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: f.Path, Kind: "member"}},
+		}
+		if f.Line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:              f.Rule,
+			Level:               "error",
+			Message:             sarifMessage{Text: "Plaintext secret at " + f.Path + " (" + f.Reason + ")"},
+			Locations:           []sarifLocation{loc},
+			PartialFingerprints: map[string]string{"secretFingerprint/v1": f.Fingerprint},
+		})
+	}
+
+	return sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           Name,
+				Version:        Version,
+				InformationURI: "https://github.com/mbevc1/aws-ssm",
+				Rules: []sarifRule{
+					{
+						ID:               ruleSecretKey,
+						ShortDescription: sarifMessage{Text: "Secret-like key stored in plaintext"},
+						Help:             sarifMessage{Text: "Store this value as a SecureString (load --auto-secure) instead of committing or storing it in plaintext."},
+					},
+					{
+						ID:               ruleSecretValue,
+						ShortDescription: sarifMessage{Text: "Credential-like value stored in plaintext"},
+						Help:             sarifMessage{Text: "The value matches a known credential format or looks random. Store it as a SecureString instead."},
+					},
+				},
+			}},
+			OriginalURIBaseIDs: map[string]sarifArtifactLocation{
+				sarifSourceRoot: {Description: &sarifMessage{Text: "Repository root"}},
+				sarifSSMRoot:    {URI: "ssm:///", Description: &sarifMessage{Text: "Parameter Store, one artifact per parameter path"}},
+			},
+			Results: results,
+		}},
+	}
+}
+
+// sarifArtifact locates a finding: a file relative to the repository root, a
+// file:// URI for files outside it, or the parameter path for "ssm"
+func sarifArtifact(f scanFinding) sarifArtifactLocation {
+	if f.Location == "ssm" {
+		return sarifArtifactLocation{URI: sarifURI(strings.TrimPrefix(f.Path, "/")), URIBaseID: sarifSSMRoot}
+	}
+	if rel, ok := repoRelative(f.Location); ok {
+		return sarifArtifactLocation{URI: sarifURI(rel), URIBaseID: sarifSourceRoot}
+	}
+	abs, err := filepath.Abs(f.Location)
+	if err != nil {
+		abs = f.Location
+	}
+	abs = filepath.ToSlash(abs)
+	if !strings.HasPrefix(abs, "/") {
+		abs = "/" + abs
+	}
+	return sarifArtifactLocation{URI: "file://" + sarifURI(abs)}
+}
+
+// sarifURI percent-encodes a slash separated path for use in a URI
+func sarifURI(path string) string {
+	return (&url.URL{Path: path}).EscapedPath()
+}
@@ -8,8 +8,8 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -18,6 +18,8 @@ var (
 	outFile    string
 	savePrefix string
 	rawOutput  bool
+
+	saveNullPlaceholder string
 )
 
 var saveCmd = &cobra.Command{
@@ -29,30 +31,41 @@ var saveCmd = &cobra.Command{
 			return fmt.Errorf("--prefix is required")
 		}
 
-		//awsCfg, err := config.LoadDefaultConfig(context.TODO())
-		var cfgOpts []func(*config.LoadOptions) error
-		if awsRegion != "" {
-			cfgOpts = append(cfgOpts, config.WithRegion(awsRegion))
-		}
-		awsCfg, err := config.LoadDefaultConfig(ctx, cfgOpts...)
+		client, err := newSSMClient()
 		if err != nil {
-			return fmt.Errorf("failed to load AWS config: %w", err)
+			return err
 		}
-
-		client := ssm.NewFromConfig(awsCfg)
 		params, err := fetchAllParameters(savePrefix, client)
 		if err != nil {
 			return err
 		}
 
+		descriptions := map[string]string{}
+		if !noDescriptions {
+			if descriptions, err = fetchDescriptions(savePrefix, client); err != nil {
+				return err
+			}
+		}
+
+		if mergeInto != "" {
+			return mergeIntoYAML(mergeInto, outFile, params, descriptions)
+		}
+
 		nested := flattenToNestedMap(params, savePrefix)
-		return writeYAML(nested, outFile)
+		if jsonOutput() {
+			return emitSave(params, nested, descriptions)
+		}
+		return writeYAML(nested, descriptions, outFile)
 	},
 }
 
 func init() {
 	saveCmd.Flags().StringVarP(&savePrefix, "prefix", "p", "", "SSM path prefix to read from (e.g. /myapp) (required)")
 	saveCmd.Flags().StringVarP(&outFile, "out", "o", "", "Output YAML file")
+	saveCmd.Flags().StringVarP(&mergeInto, "merge-into", "m", "", "Update this existing YAML file in place, keeping its key order, comments and anchors")
+	saveCmd.Flags().BoolVar(&mergePrune, "prune", false, "With --merge-into, remove keys that are no longer in SSM")
+	saveCmd.Flags().BoolVar(&noDescriptions, "no-descriptions", false, "Don't write parameter descriptions as YAML comments")
+	saveCmd.Flags().StringVar(&saveNullPlaceholder, "null-placeholder", "", "Write values equal to this placeholder back as YAML null (see load --on-null placeholder)")
 	saveCmd.Flags().BoolVar(&rawOutput, "raw", false, "Disable list conversion, output all maps")
 }
 
@@ -89,6 +102,30 @@ func fetchAllParameters(prefix string, client *ssm.Client) (map[string]string, e
 	return results, nil
 }
 
+// fetchDescriptions returns the description of every parameter below prefix
+// that has one. GetParametersByPath doesn't return them.
+func fetchDescriptions(prefix string, client *ssm.Client) (map[string]string, error) {
+	var filters []types.ParameterStringFilter
+	if strings.Trim(prefix, "/") != "" {
+		filters = append(filters, types.ParameterStringFilter{
+			Key:    aws.String("Path"),
+			Option: aws.String("Recursive"),
+			Values: []string{"/" + strings.Trim(prefix, "/")},
+		})
+	}
+	metas, err := describeParameters(filters, client)
+	if err != nil {
+		return nil, err
+	}
+	descriptions := make(map[string]string)
+	for _, m := range metas {
+		if desc := aws.ToString(m.Description); desc != "" {
+			descriptions[aws.ToString(m.Name)] = desc
+		}
+	}
+	return descriptions, nil
+}
+
 func flattenToNestedMap(flat map[string]string, prefix string) map[string]interface{} {
 	tree := make(map[string]interface{})
 
@@ -179,21 +216,69 @@ func isNumericMap(m map[string]interface{}) bool {
 	return true
 }
 
+// parseTypedValue turns scalars back into YAML booleans and numbers. Other values
+// are returned untouched, so multi-line values keep their trailing newlines and
+// are written as literal block scalars. A value is only typed when YAML writes it
+// back unchanged: "1", "007", "1.50" or " true" stay strings rather than becoming
+// true, 7, 1.5 or true, which load would upload as different values.
 func parseTypedValue(s string) interface{} {
-	s = strings.TrimSpace(s)
+	if saveNullPlaceholder != "" && s == saveNullPlaceholder {
+		return nil
+	}
+	var candidates []interface{}
 	if b, err := strconv.ParseBool(s); err == nil {
-		return b
+		candidates = append(candidates, b)
 	}
 	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
-		return i
+		candidates = append(candidates, i)
 	}
 	if f, err := strconv.ParseFloat(s, 64); err == nil {
-		return f
+		candidates = append(candidates, f)
+	}
+	for _, typed := range candidates {
+		if newScalarNode(typed).Value == s {
+			return typed
+		}
 	}
 	return s
 }
 
-func writeYAML(data map[string]interface{}, path string) error {
+// emitSave reports every saved parameter as an event. The YAML document is still
+// written when -o names a file, otherwise the nested tree goes into a final event.
+func emitSave(params map[string]string, nested map[string]interface{}, descriptions map[string]string) error {
+	for _, name := range sortedKeys(params) {
+		data := map[string]string{"value": params[name]}
+		if desc := descriptions[name]; desc != "" {
+			data["description"] = desc
+		}
+		emit(event{Operation: "save", Path: name, Result: resultOK, Data: data})
+	}
+	if outFile == "" || outFile == "-" {
+		emit(event{Operation: "save", Path: savePrefix, Result: resultOK, Data: nested})
+		return nil
+	}
+	if err := writeYAML(nested, descriptions, outFile); err != nil {
+		return err
+	}
+	emit(event{Operation: "save", Path: outFile, Result: resultOK, Message: "written"})
+	return nil
+}
+
+// writeYAML writes data as YAML, with descriptions (keyed by parameter name) as
+// comments above their keys
+func writeYAML(data map[string]interface{}, descriptions map[string]string, path string) error {
+	var doc yaml.Node
+	if err := doc.Encode(data); err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if len(descriptions) > 0 {
+		walkYAMLLeaves(&doc, "/"+strings.Trim(savePrefix, "/"), func(name string, key, leaf *yaml.Node) {
+			if desc := descriptions[name]; desc != "" {
+				setLeafComment(key, leaf, desc)
+			}
+		})
+	}
+
 	var out *os.File
 	var err error
 
@@ -209,5 +294,5 @@ func writeYAML(data map[string]interface{}, path string) error {
 
 	enc := yaml.NewEncoder(out)
 	enc.SetIndent(2)
-	return enc.Encode(data)
+	return enc.Encode(&doc)
 }
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseTypedValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want interface{}
+	}{
+		{"true", true},
+		{"false", false},
+		{"1", int64(1)},
+		{"0", int64(0)},
+		{"-3", int64(-3)},
+		{"5432", int64(5432)},
+		{"1.5", 1.5},
+		{"True", "True"},
+		{"t", "t"},
+		{"007", "007"},
+		{"1.50", "1.50"},
+		{"1.0", "1.0"},
+		{"1e3", "1e3"},
+		{"inf", "inf"},
+		{"db.internal", "db.internal"},
+		{"line1\nline2\n", "line1\nline2\n"},
+		{"5432\n", "5432\n"},
+		{" true", " true"},
+		{"42 ", "42 "},
+	}
+	for _, tt := range tests {
+		if got := parseTypedValue(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTypedValue(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	params := map[string]string{
+		"/app/port":    "5432",
+		"/app/padded":  "5432\n",
+		"/app/flag":    "true",
+		"/app/spaced":  " true",
+		"/app/one":     "1",
+		"/app/zip":     "007",
+		"/app/cert":    "line1\nline2\n",
+		"/app/db/host": "db.internal ",
+	}
+	raw, err := yaml.Marshal(flattenToNestedMap(params, "/app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := yamlLeafValues(raw, "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, params) {
+		t.Errorf("load after save gives %q, want %q\n%s", got, params, raw)
+	}
+}
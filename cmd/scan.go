@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	scanPrefixes      []string
+	scanBaseline      string
+	scanWriteBaseline bool
+	scanFormat        string
+	scanOutFile       string
+)
+
+// Rule IDs reported by scan (and used as SARIF rule IDs)
+const (
+	ruleSecretKey   = "secret-key"
+	ruleSecretValue = "secret-value"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [file.yaml...]",
+	Short: "Find plaintext secrets in YAML files and String parameters",
+	Long: `Find plaintext secrets in YAML files and String parameters.
+
+Every leaf of the given YAML files, and every String / StringList parameter below
+--prefix, is checked with the secret detector (key names and values). Findings
+listed in the --baseline file, or matching one of its "allow" globs, are ignored.
+The command exits with an error when new findings remain, so it can gate CI.`,
+	Aliases: []string{"sc"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && len(scanPrefixes) == 0 {
+			return fmt.Errorf("give YAML files to scan and/or --prefix")
+		}
+		switch scanFormat {
+		case "text", "json", "sarif":
+		default:
+			return fmt.Errorf("unsupported --format %q (use text, json or sarif)", scanFormat)
+		}
+		if jsonOutput() && scanFormat != "text" && (scanOutFile == "" || scanOutFile == "-") {
+			return fmt.Errorf("--format %s with --output json needs --out FILE, stdout carries the events", scanFormat)
+		}
+
+		var findings []scanFinding
+		for _, file := range args {
+			found, err := scanYAMLFile(file)
+			if err != nil {
+				return err
+			}
+			findings = append(findings, found...)
+		}
+		if len(scanPrefixes) > 0 {
+			found, err := scanParameters(scanPrefixes)
+			if err != nil {
+				return err
+			}
+			findings = append(findings, found...)
+		}
+
+		if scanWriteBaseline {
+			if scanBaseline == "" {
+				return fmt.Errorf("--write-baseline needs --baseline")
+			}
+			return writeScanBaseline(scanBaseline, findings)
+		}
+
+		baseline := &scanBaselineFile{}
+		if scanBaseline != "" {
+			var err error
+			if baseline, err = readScanBaseline(scanBaseline); err != nil {
+				return err
+			}
+		}
+		var fresh []scanFinding
+		for _, f := range findings {
+			if !baseline.accepts(f) {
+				fresh = append(fresh, f)
+			}
+		}
+
+		if err := reportFindings(fresh, len(findings)-len(fresh)); err != nil {
+			return err
+		}
+		if len(fresh) > 0 {
+			return fmt.Errorf("%d plaintext secret(s) found", len(fresh))
+		}
+		return nil
+	},
+}
+
+func init() {
+	scanCmd.Flags().StringSliceVarP(&scanPrefixes, "prefix", "p", nil, "Also scan String parameters below these SSM prefixes (repeatable)")
+	scanCmd.Flags().StringVarP(&scanBaseline, "baseline", "B", "", "Baseline file of accepted findings and allowed globs")
+	scanCmd.Flags().BoolVar(&scanWriteBaseline, "write-baseline", false, "Write all current findings to --baseline instead of reporting them")
+	scanCmd.Flags().StringVarP(&scanFormat, "format", "F", "text", "Output format: text, json or sarif")
+	scanCmd.Flags().StringVarP(&scanOutFile, "out", "o", "", "Write the report to a file instead of stdout")
+}
+
+// scanFinding is one plaintext secret: a YAML leaf (Location is the file) or a
+// String parameter (Location is "ssm")
+type scanFinding struct {
+	Location    string `json:"location"`
+	Path        string `json:"path"`
+	Line        int    `json:"line,omitempty"`
+	Rule        string `json:"rule"`
+	Reason      string `json:"reason"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// newScanFinding classifies a leaf and returns a finding if it looks like a secret
+func newScanFinding(location, path, value string, line int) (scanFinding, bool) {
+	if value == "" {
+		return scanFinding{}, false
+	}
+	rule := ruleSecretKey
+	secret, reason := detector.classifyLeaf(path, value, true, false)
+	if !secret {
+		rule = ruleSecretValue
+		secret, reason = detector.classifyLeaf(path, value, false, true)
+	}
+	if !secret {
+		return scanFinding{}, false
+	}
+	return scanFinding{
+		Location:    location,
+		Path:        path,
+		Line:        line,
+		Rule:        rule,
+		Reason:      reason,
+		Fingerprint: scanFingerprint(location, path, rule),
+	}, true
+}
+
+// scanFingerprint identifies a finding by where it is and which rule fired. The
+// value is left out on purpose: baselines get committed, and a plain hash of a
+// short secret can be brute-forced.
+func scanFingerprint(location, path, rule string) string {
+	if rel, ok := repoRelative(location); ok {
+		location = rel
+	}
+	sum := sha256.Sum256([]byte(location + "\x00" + path + "\x00" + rule))
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+// repoRoot returns the closest directory at or above the working directory that
+// holds .git, or the working directory itself
+func repoRoot() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	for dir := wd; ; {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return wd
+		}
+		dir = parent
+	}
+}
+
+// repoRelative turns file into a slash separated path relative to repoRoot, so
+// fingerprints and reports don't depend on where the repository is checked out.
+// It returns false for "ssm" and for files outside the repository.
+func repoRelative(file string) (string, bool) {
+	if file == "ssm" {
+		return "", false
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(repoRoot(), abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func scanYAMLFile(file string) ([]scanFinding, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	var findings []scanFinding
+	walkYAMLLeaves(&doc, "", func(path string, _, leaf *yaml.Node) {
+		if isNullNode(leaf) {
+			return
+		}
+		if f, ok := newScanFinding(file, path, leaf.Value, leaf.Line); ok {
+			findings = append(findings, f)
+		}
+	})
+	return findings, nil
+}
+
+// scanParameters checks the String and StringList parameters below prefixes.
+// SecureStrings are skipped without being decrypted.
+func scanParameters(prefixes []string) ([]scanFinding, error) {
+	client, err := newSSMClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []scanFinding
+	for _, prefix := range prefixes {
+		params, err := fetchAllParameterObjects("/"+strings.Trim(prefix, "/"), false, client)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range sortedParamNames(params) {
+			p := params[name]
+			if p.Type == types.ParameterTypeSecureString {
+				continue
+			}
+			if f, ok := newScanFinding("ssm", name, p.Value, 0); ok {
+				findings = append(findings, f)
+			}
+		}
+	}
+	return findings, nil
+}
+
+func sortedParamNames(params map[string]treeParam) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scanBaselineFile lists accepted findings by fingerprint, plus globs of paths that
+// are never reported
+type scanBaselineFile struct {
+	Allow    []string      `json:"allow,omitempty"`
+	Findings []scanFinding `json:"findings"`
+
+	known map[string]bool
+}
+
+func (b *scanBaselineFile) accepts(f scanFinding) bool {
+	if b.known == nil {
+		b.known = make(map[string]bool, len(b.Findings))
+		for _, bf := range b.Findings {
+			b.known[bf.Fingerprint] = true
+		}
+	}
+	if b.known[f.Fingerprint] {
+		return true
+	}
+	for _, pattern := range b.Allow {
+		if globMatch(pattern, f.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+func readScanBaseline(file string) (*scanBaselineFile, error) {
+	raw, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return &scanBaselineFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var b scanBaselineFile
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", file, err)
+	}
+	return &b, nil
+}
+
+// writeScanBaseline records findings, keeping the allow list of an existing baseline
+func writeScanBaseline(file string, findings []scanFinding) error {
+	b, err := readScanBaseline(file)
+	if err != nil {
+		return err
+	}
+	b.Findings = findings
+	if b.Findings == nil {
+		b.Findings = []scanFinding{}
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	fmt.Fprintf(humanOut(), "Wrote %d finding(s) to %s\n", len(findings), file)
+	return nil
+}
+
+// reportFindings writes the report in --format to --out or stdout. With --output
+// json stdout carries one event per finding instead, and the report still goes
+// to --out when given, so CI jobs get their SARIF file either way.
+func reportFindings(findings []scanFinding, baselined int) error {
+	toFile := scanOutFile != "" && scanOutFile != "-"
+	if jsonOutput() {
+		for _, f := range findings {
+			emit(event{Operation: "scan", Path: f.Path, Result: resultError, ErrorCode: f.Rule, Message: f.Reason, Data: f})
+		}
+		if !toFile {
+			return nil
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if toFile {
+		file, err := os.Create(scanOutFile)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	return writeScanReport(out, findings, baselined)
+}
+
+func writeScanReport(out io.Writer, findings []scanFinding, baselined int) error {
+	switch scanFormat {
+	case "json":
+		if findings == nil {
+			findings = []scanFinding{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(findings)
+	case "sarif":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sarifReport(findings))
+	}
+
+	for _, f := range findings {
+		location := f.Location
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", f.Location, f.Line)
+		}
+		fmt.Fprintf(out, "%s %s %s %s\n", icon(markWarn), style(roleMuted).Sprint(location), style(roleName).Sprint(f.Path), style(roleWarning).Sprintf("(%s)", f.Reason))
+	}
+	summary := fmt.Sprintf("%d finding(s)", len(findings))
+	if baselined > 0 {
+		summary += fmt.Sprintf(", %d accepted by the baseline", baselined)
+	}
+	fmt.Fprintf(out, "\n%s\n", summary)
+	return nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanFingerprint(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := scanFingerprint("config/app.yaml", "/db/password", ruleSecretKey)
+	tests := []struct {
+		name                 string
+		location, path, rule string
+		same                 bool
+	}{
+		{"absolute path of the same file", filepath.Join(wd, "config", "app.yaml"), "/db/password", ruleSecretKey, true},
+		{"dot prefix", "./config/app.yaml", "/db/password", ruleSecretKey, true},
+		{"other file", "config/other.yaml", "/db/password", ruleSecretKey, false},
+		{"other path", "config/app.yaml", "/db/token", ruleSecretKey, false},
+		{"other rule", "config/app.yaml", "/db/password", ruleSecretValue, false},
+		{"ssm", "ssm", "/db/password", ruleSecretKey, false},
+	}
+	for _, tt := range tests {
+		got := scanFingerprint(tt.location, tt.path, tt.rule)
+		if (got == base) != tt.same {
+			t.Errorf("%s: fingerprint %s, same as base = %v, want %v", tt.name, got, got == base, tt.same)
+		}
+	}
+}
+
+func TestNewScanFindingFingerprint(t *testing.T) {
+	// Rotating a plaintext value keeps the baselined fingerprint, and the value never enters it
+	a, okA := newScanFinding("app.yaml", "/db/password", "hunter2", 3)
+	b, okB := newScanFinding("app.yaml", "/db/password", "correct horse", 3)
+	if !okA || !okB {
+		t.Fatal("expected findings for /db/password")
+	}
+	if a.Fingerprint != b.Fingerprint {
+		t.Errorf("fingerprint changed with the value: %s vs %s", a.Fingerprint, b.Fingerprint)
+	}
+}
+
+func TestSarifArtifact(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(filepath.Dir(repoRoot()), "elsewhere", "app.yaml")
+	tests := []struct {
+		name    string
+		finding scanFinding
+		uri     string
+		base    string
+	}{
+		{"relative file", scanFinding{Location: "config/app.yaml"}, relToRoot(t, filepath.Join(wd, "config/app.yaml")), sarifSourceRoot},
+		{"absolute file", scanFinding{Location: filepath.Join(wd, "a b.yaml")}, strings.ReplaceAll(relToRoot(t, filepath.Join(wd, "a b.yaml")), " ", "%20"), sarifSourceRoot},
+		{"outside the repository", scanFinding{Location: outside}, "file://" + filepath.ToSlash(outside), ""},
+		{"parameter", scanFinding{Location: "ssm", Path: "/myapp/db/password"}, "myapp/db/password", sarifSSMRoot},
+	}
+	for _, tt := range tests {
+		got := sarifArtifact(tt.finding)
+		if got.URI != tt.uri || got.URIBaseID != tt.base {
+			t.Errorf("%s: got %s (%s), want %s (%s)", tt.name, got.URI, got.URIBaseID, tt.uri, tt.base)
+		}
+	}
+}
+
+func relToRoot(t *testing.T, file string) string {
+	rel, err := filepath.Rel(repoRoot(), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func TestReportFindings(t *testing.T) {
+	findings := []scanFinding{{Location: "app.yaml", Path: "/db/password", Line: 3, Rule: ruleSecretKey, Reason: "key name matches password", Fingerprint: "abc"}}
+	tests := []struct {
+		name, output, format string
+		toFile               bool
+		wantStdout, wantFile string
+	}{
+		{"json report", "text", "json", false, `"fingerprint": "abc"`, ""},
+		{"report to file", "text", "sarif", true, "", `"ruleId": "secret-key"`},
+		{"events and sarif file", "json", "sarif", true, `"operation":"scan","path":"/db/password","result":"error","error_code":"secret-key"`, `"ruleId": "secret-key"`},
+		{"events only", "json", "text", false, `"operation":"scan"`, ""},
+	}
+	defer func(o, f, out string) { outputMode, scanFormat, scanOutFile = o, f, out }(outputMode, scanFormat, scanOutFile)
+	for _, tt := range tests {
+		outputMode, scanFormat, scanOutFile = tt.output, tt.format, ""
+		if tt.toFile {
+			scanOutFile = filepath.Join(t.TempDir(), "report")
+		}
+		var err error
+		stdout := captureStdout(t, func() { err = reportFindings(findings, 0) })
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !strings.Contains(stdout, tt.wantStdout) || (tt.wantStdout == "" && stdout != "") {
+			t.Errorf("%s: stdout %q, want it to contain %q", tt.name, stdout, tt.wantStdout)
+		}
+		if tt.toFile {
+			file, _ := os.ReadFile(scanOutFile)
+			if !strings.Contains(string(file), tt.wantFile) {
+				t.Errorf("%s: report file %q, want it to contain %q", tt.name, file, tt.wantFile)
+			}
+		}
+	}
+}
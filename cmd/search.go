@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchPrefixes   []string
+	searchRegex      bool
+	searchIgnoreCase bool
+	searchValues     bool
+	searchDecrypt    bool
+)
+
+// searchContext is how many characters around a value match are shown
+const searchContext = 20
+
+var searchCmd = &cobra.Command{
+	Use:     "search <pattern>",
+	Short:   "Search parameter names (and optionally values) for a substring or regex",
+	Long:    "Search parameter names (and optionally values) for a substring or regex. Without --prefix the whole account is searched via DescribeParameters.",
+	Aliases: []string{"find", "grep"},
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		expr := args[0]
+		if !searchRegex {
+			expr = regexp.QuoteMeta(expr)
+		}
+		if searchIgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		params, err := searchCandidates(client)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(params))
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		matches := 0
+		for _, name := range names {
+			param := params[name]
+			nameSpans, valueSpans := matchParameter(re, name, param)
+			if len(nameSpans) == 0 && len(valueSpans) == 0 {
+				continue
+			}
+			matches++
+
+			e := event{Operation: "search", Path: name, Type: string(param.Type), Result: resultOK}
+			if len(valueSpans) > 0 {
+				e.Data = map[string]string{"value": param.Value}
+			}
+			if emit(e) {
+				continue
+			}
+
+			label := highlightMatches(name, nameSpans, labelColor(param.Type))
+			if param.Type == types.ParameterTypeSecureString {
+				label += labelColor(param.Type).Sprint(" " + icon(markSecure))
+			}
+			if len(valueSpans) > 0 {
+				label += " = " + valueContext(param.Value, valueSpans)
+			}
+			fmt.Println(label)
+		}
+
+		fmt.Fprintf(humanOut(), "\n%d matches in %d parameters\n", matches, len(params))
+		return nil
+	},
+}
+
+func init() {
+	searchCmd.Flags().StringSliceVarP(&searchPrefixes, "prefix", "p", nil, "SSM path prefixes to search (repeatable, default: whole account)")
+	searchCmd.Flags().BoolVarP(&searchRegex, "regex", "E", false, "Treat the pattern as a regular expression")
+	searchCmd.Flags().BoolVarP(&searchIgnoreCase, "ignore-case", "i", false, "Case insensitive matching")
+	searchCmd.Flags().BoolVarP(&searchValues, "values", "v", false, "Also search parameter values (SecureStrings only with --decrypt)")
+	searchCmd.Flags().BoolVarP(&searchDecrypt, "decrypt", "d", false, "Decrypt SecureString values so they can be searched (requires IAM permission)")
+}
+
+// searchCandidates returns the parameters to search: everything below the given
+// prefixes, or the whole account when none were given
+func searchCandidates(client *ssm.Client) (map[string]treeParam, error) {
+	if len(searchPrefixes) > 0 {
+		result := make(map[string]treeParam)
+		for _, prefix := range searchPrefixes {
+			found, err := fetchAllParameterObjects("/"+strings.Trim(prefix, "/"), searchDecrypt, client)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range found {
+				result[k] = v
+			}
+		}
+		return result, nil
+	}
+
+	meta, err := describeParameters(nil, client)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]treeParam, len(meta))
+	names := make([]string, 0, len(meta))
+	for _, m := range meta {
+		result[aws.ToString(m.Name)] = treeParam{Type: m.Type}
+		names = append(names, aws.ToString(m.Name))
+	}
+	if !searchValues {
+		return result, nil
+	}
+
+	// DescribeParameters carries no values, fetch them in batches
+	for start := 0; start < len(names); start += ssmBatchSize {
+		batch := names[start:min(start+ssmBatchSize, len(names))]
+		out, err := client.GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          batch,
+			WithDecryption: aws.Bool(searchDecrypt),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching parameters: %w", err)
+		}
+		for _, p := range out.Parameters {
+			result[*p.Name] = treeParam{Type: p.Type, Value: aws.ToString(p.Value)}
+		}
+	}
+	return result, nil
+}
+
+// matchParameter returns the spans of re in the name and, with --values, the value of a parameter
+func matchParameter(re *regexp.Regexp, name string, param treeParam) (nameSpans, valueSpans [][]int) {
+	nameSpans = re.FindAllStringIndex(name, -1)
+	// Without --decrypt a SecureString value is ciphertext, which would only give noise
+	if searchValues && (searchDecrypt || param.Type != types.ParameterTypeSecureString) {
+		valueSpans = re.FindAllStringIndex(param.Value, -1)
+	}
+	return nameSpans, valueSpans
+}
+
+// highlightMatches colors the matched spans of s and renders the rest with base
+func highlightMatches(s string, spans [][]int, base *color.Color) string {
+	hl := style(roleMatch)
+	var sb strings.Builder
+	last := 0
+	for _, span := range spans {
+		sb.WriteString(base.Sprint(s[last:span[0]]))
+		sb.WriteString(hl.Sprint(s[span[0]:span[1]]))
+		last = span[1]
+	}
+	sb.WriteString(base.Sprint(s[last:]))
+	return sb.String()
+}
+
+// valueContext shows the value around the first match, trimmed to searchContext
+// characters on each side
+func valueContext(value string, spans [][]int) string {
+	start := max(spans[0][0]-searchContext, 0)
+	end := min(spans[0][1]+searchContext, len(value))
+	// Don't cut multi-byte characters in half
+	for start > 0 && !isRuneStart(value[start]) {
+		start--
+	}
+	for end < len(value) && !isRuneStart(value[end]) {
+		end++
+	}
+
+	var shifted [][]int
+	for _, span := range spans {
+		if span[0] >= start && span[1] <= end {
+			shifted = append(shifted, []int{span[0] - start, span[1] - start})
+		}
+	}
+
+	// Newlines are swapped for spaces (same byte length, so spans stay valid)
+	out := highlightMatches(strings.ReplaceAll(value[start:end], "\n", " "), shifted, style(roleMuted))
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(value) {
+		out += "…"
+	}
+	return out
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/fatih/color"
+)
+
+func TestValueContext(t *testing.T) {
+	defer func(n bool) { color.NoColor = n }(color.NoColor)
+	color.NoColor = true
+
+	long := strings.Repeat("a", 30) + "needle" + strings.Repeat("b", 30)
+	tests := []struct {
+		value, pattern string
+		want           string
+	}{
+		{"short needle value", "needle", "short needle value"},
+		{long, "needle", "…" + strings.Repeat("a", 20) + "needle" + strings.Repeat("b", 20) + "…"},
+		{"needle" + strings.Repeat("x", 30), "needle", "needle" + strings.Repeat("x", 20) + "…"},
+		{"line one\nneedle\nline three", "needle", "line one needle line three"},
+		{strings.Repeat("é", 15) + "needle", "needle", "…" + strings.Repeat("é", 10) + "needle"},
+	}
+	for _, tt := range tests {
+		spans := regexp.MustCompile(tt.pattern).FindAllStringIndex(tt.value, -1)
+		if got := valueContext(tt.value, spans); got != tt.want {
+			t.Errorf("valueContext(%q):\ngot  %q\nwant %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMatchParameter(t *testing.T) {
+	re := regexp.MustCompile("db")
+	tests := []struct {
+		name            string
+		param           treeParam
+		values, decrypt bool
+		wantName        int
+		wantValue       int
+	}{
+		{"/app/db/host", treeParam{Type: types.ParameterTypeString, Value: "db.internal"}, false, false, 1, 0},
+		{"/app/db/host", treeParam{Type: types.ParameterTypeString, Value: "db.internal"}, true, false, 1, 1},
+		{"/app/host", treeParam{Type: types.ParameterTypeString, Value: "db1,db2"}, true, false, 0, 2},
+		{"/app/url", treeParam{Type: types.ParameterTypeSecureString, Value: "AQICAHdb"}, true, false, 0, 0},
+		{"/app/url", treeParam{Type: types.ParameterTypeSecureString, Value: "postgres://db"}, true, true, 0, 1},
+	}
+	defer func(v, d bool) { searchValues, searchDecrypt = v, d }(searchValues, searchDecrypt)
+	for _, tt := range tests {
+		searchValues, searchDecrypt = tt.values, tt.decrypt
+		nameSpans, valueSpans := matchParameter(re, tt.name, tt.param)
+		if len(nameSpans) != tt.wantName || len(valueSpans) != tt.wantValue {
+			t.Errorf("%s (%s, values=%v, decrypt=%v): %d name and %d value matches, want %d and %d",
+				tt.name, tt.param.Type, tt.values, tt.decrypt, len(nameSpans), len(valueSpans), tt.wantName, tt.wantValue)
+		}
+	}
+}
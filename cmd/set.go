@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setType        string
+	setKeyID       string
+	setTier        string
+	setDescription string
+	setOverwrite   bool
+	setValueFile   string
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set <name> [value]",
+	Short: "Create or update a single parameter (value from argument, --value-file or stdin)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		paramType, err := parseParameterType(setType)
+		if err != nil {
+			return err
+		}
+		tier, err := parseParameterTier(setTier)
+		if err != nil {
+			return err
+		}
+		if setKeyID != "" && paramType != types.ParameterTypeSecureString {
+			return fmt.Errorf("--key-id can only be used with --type SecureString")
+		}
+
+		value, err := readSetValue(args)
+		if err != nil {
+			return err
+		}
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		if setOverwrite {
+			if err := takeBackup("set", []string{name}, client); err != nil {
+				return err
+			}
+		}
+
+		input := &ssm.PutParameterInput{
+			Name:      aws.String(name),
+			Value:     aws.String(value),
+			Type:      paramType,
+			Tier:      tier,
+			Overwrite: aws.Bool(setOverwrite),
+		}
+		if setKeyID != "" {
+			input.KeyId = aws.String(setKeyID)
+		}
+		if setDescription != "" {
+			input.Description = aws.String(setDescription)
+		}
+
+		out, err := client.PutParameter(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to set %s: %s", name, extractMessage(err))
+		}
+
+		if emit(event{Operation: "set", Path: name, Type: string(paramType), Result: resultOK, Data: map[string]interface{}{"version": out.Version, "tier": out.Tier}}) {
+			return nil
+		}
+		lockIcon := ""
+		if paramType == types.ParameterTypeSecureString {
+			lockIcon = " " + icon(markSecure)
+		}
+		fmt.Printf("%s Set %s%s %s\n", icon(markOK), name, lockIcon, style(roleMuted).Sprintf("(v%d, %s)", out.Version, out.Tier))
+		return nil
+	},
+}
+
+func init() {
+	setCmd.Flags().StringVarP(&setType, "type", "t", "String", "Parameter type: String, SecureString or StringList")
+	setCmd.Flags().StringVarP(&setKeyID, "key-id", "k", "", "KMS key ID for SecureString (defaults to the AWS managed key)")
+	setCmd.Flags().StringVar(&setTier, "tier", "", "Parameter tier: standard, advanced or intelligent-tiering")
+	setCmd.Flags().StringVarP(&setDescription, "description", "D", "", "Parameter description")
+	setCmd.Flags().BoolVarP(&setOverwrite, "overwrite", "o", false, "Allow overwriting an existing parameter")
+	setCmd.Flags().StringVar(&setValueFile, "value-file", "", "Read the value from a file (\"-\" for stdin)")
+	setCmd.Flags().BoolVar(&noBackup, "no-backup", false, "Don't write a backup snapshot before overwriting")
+}
+
+// readSetValue takes the value from the second argument, --value-file, or piped stdin.
+// A single trailing newline is dropped from files and stdin.
+func readSetValue(args []string) (string, error) {
+	if len(args) == 2 {
+		if setValueFile != "" {
+			return "", fmt.Errorf("pass the value either as an argument or with --value-file, not both")
+		}
+		if args[1] != "-" {
+			return args[1], nil
+		}
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case setValueFile != "" && setValueFile != "-":
+		data, err = os.ReadFile(setValueFile)
+	case setValueFile == "-" || (len(args) == 2 && args[1] == "-") || !isTerminal(os.Stdin):
+		data, err = io.ReadAll(os.Stdin)
+	default:
+		return "", fmt.Errorf("a value is required (argument, --value-file or stdin)")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read value: %w", err)
+	}
+
+	value := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if value == "" {
+		return "", fmt.Errorf("parameter values can't be empty")
+	}
+	return value, nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func parseParameterType(s string) (types.ParameterType, error) {
+	for _, t := range types.ParameterTypeString.Values() {
+		if strings.EqualFold(string(t), s) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported --type %q (use String, SecureString or StringList)", s)
+}
+
+// parseParameterTier maps CLI spellings to tiers; "" keeps the account default
+func parseParameterTier(s string) (types.ParameterTier, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return "", nil
+	case "standard":
+		return types.ParameterTierStandard, nil
+	case "advanced":
+		return types.ParameterTierAdvanced, nil
+	case "intelligent-tiering", "intelligent":
+		return types.ParameterTierIntelligentTiering, nil
+	}
+	return "", fmt.Errorf("unsupported --tier %q (use standard, advanced or intelligent-tiering)", s)
+}
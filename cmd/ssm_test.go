@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// fakeSSM is an in-memory Parameter Store speaking the SSM JSON protocol, enough
+// of it for the commands under test
+type fakeSSM struct {
+	mu     sync.Mutex
+	params map[string]fakeParam
+	// denied names can't be read or deleted; a batch call that includes one is
+	// denied as a whole, like an IAM policy scoped to paths
+	denied map[string]bool
+	// calls records every operation with the names it asked for
+	calls []string
+}
+
+type fakeParam struct {
+	Type  string
+	Value string
+}
+
+// newFakeSSM starts a fake holding params (name -> value, all String) and returns
+// a client talking to it
+func newFakeSSM(t *testing.T, params map[string]string) (*fakeSSM, *ssm.Client) {
+	t.Helper()
+	f := &fakeSSM{params: make(map[string]fakeParam), denied: make(map[string]bool)}
+	for name, value := range params {
+		f.params[name] = fakeParam{Type: "String", Value: value}
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client := ssm.New(ssm.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(srv.URL),
+		Credentials:  aws.AnonymousCredentials{},
+		Retryer:      aws.NopRetryer{},
+	})
+	return f, client
+}
+
+func (f *fakeSSM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var in struct {
+		Name             string
+		Names            []string
+		Path             string
+		NextToken        string
+		ParameterFilters []struct {
+			Key    string
+			Values []string
+		}
+	}
+	json.NewDecoder(r.Body).Decode(&in)
+	op := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "AmazonSSM.")
+	names := in.Names
+	if in.Name != "" {
+		names = []string{in.Name}
+	}
+	f.calls = append(f.calls, op+" "+strings.Join(names, ","))
+
+	for _, name := range names {
+		if f.denied[name] {
+			w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"__type": "AccessDeniedException", "message": "not allowed: " + name})
+			return
+		}
+	}
+
+	var out interface{}
+	switch op {
+	case "GetParameter":
+		p, ok := f.params[in.Name]
+		if !ok {
+			w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"__type": "ParameterNotFound", "message": in.Name})
+			return
+		}
+		out = map[string]interface{}{"Parameter": f.parameter(in.Name, p)}
+	case "GetParameters":
+		found, invalid := []interface{}{}, []string{}
+		for _, name := range in.Names {
+			if p, ok := f.params[name]; ok {
+				found = append(found, f.parameter(name, p))
+			} else {
+				invalid = append(invalid, name)
+			}
+		}
+		out = map[string]interface{}{"Parameters": found, "InvalidParameters": invalid}
+	case "GetParametersByPath":
+		found := []interface{}{}
+		for _, name := range f.sortedNames() {
+			if strings.HasPrefix(name, strings.TrimSuffix(in.Path, "/")+"/") {
+				found = append(found, f.parameter(name, f.params[name]))
+			}
+		}
+		out = map[string]interface{}{"Parameters": found}
+	case "DescribeParameters":
+		path := "/"
+		for _, filter := range in.ParameterFilters {
+			if filter.Key == "Path" {
+				path = strings.TrimSuffix(filter.Values[0], "/") + "/"
+			}
+		}
+		found := []interface{}{}
+		for _, name := range f.sortedNames() {
+			if strings.HasPrefix(name, path) {
+				found = append(found, map[string]interface{}{"Name": name, "Type": f.params[name].Type, "Version": 1})
+			}
+		}
+		out = map[string]interface{}{"Parameters": found}
+	case "DeleteParameters":
+		deleted, invalid := []string{}, []string{}
+		for _, name := range in.Names {
+			if _, ok := f.params[name]; ok {
+				delete(f.params, name)
+				deleted = append(deleted, name)
+			} else {
+				invalid = append(invalid, name)
+			}
+		}
+		out = map[string]interface{}{"DeletedParameters": deleted, "InvalidParameters": invalid}
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	json.NewEncoder(w).Encode(out)
+}
+
+func (f *fakeSSM) parameter(name string, p fakeParam) map[string]interface{} {
+	return map[string]interface{}{"Name": name, "Type": p.Type, "Value": p.Value, "Version": 1}
+}
+
+func (f *fakeSSM) sortedNames() []string {
+	names := make([]string, 0, len(f.params))
+	for name := range f.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
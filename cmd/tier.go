@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/spf13/cobra"
+)
+
+// loadTier is --tier of load and validate: a Parameter Store tier or tierAuto
+var loadTier string
+
+// tierAuto uses the Standard tier and switches to Advanced only for values over 4 KB
+const tierAuto = "auto"
+
+const (
+	// maxAdvancedValue is the value size limit of the Advanced tier
+	maxAdvancedValue = 8192
+	// advancedMonthlyCost is the list price in USD of one Advanced parameter per month
+	advancedMonthlyCost = 0.05
+)
+
+// addTierFlag registers --tier, shared by load and validate
+func addTierFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&loadTier, "tier", tierAuto, "Parameter tier: auto (Advanced only for values over 4 KB), standard, advanced or intelligent-tiering")
+}
+
+func validateTierFlag() error {
+	if loadTier == tierAuto {
+		return nil
+	}
+	if _, err := parseParameterTier(loadTier); err != nil || loadTier == "" {
+		return fmt.Errorf("unsupported --tier %q (use auto, standard, advanced or intelligent-tiering)", loadTier)
+	}
+	return nil
+}
+
+// valueTier returns the tier to request for value. An empty tier leaves the
+// choice to Parameter Store, so existing Advanced parameters aren't downgraded.
+// Policies never promote a value here: they are only kept when --tier already
+// allows Advanced parameters (see dropPoliciesWithoutAdvanced).
+func valueTier(value string) types.ParameterTier {
+	if loadTier != tierAuto {
+		tier, _ := parseParameterTier(loadTier)
+		return tier
+	}
+	if len(value) > maxStandardValue {
+		return types.ParameterTierAdvanced
+	}
+	return ""
+}
+
+// standardTierOnly reports whether --tier keeps every value in the Standard tier
+func standardTierOnly() bool {
+	tier, _ := parseParameterTier(loadTier)
+	return tier == types.ParameterTierStandard
+}
+
+// advancedTierAllowed reports whether --tier was explicitly set to a tier that
+// may store Advanced parameters, which policies need
+func advancedTierAllowed() bool {
+	tier, _ := parseParameterTier(loadTier)
+	return loadTier != tierAuto && (tier == types.ParameterTierAdvanced || tier == types.ParameterTierIntelligentTiering)
+}
+
+// reportAdvancedTier prints how many parameters of a load end up in the Advanced
+// tier and what they cost per month, before anything is written
+func reportAdvancedTier(leaves []yamlLeaf, policies map[string]string) {
+	advanced := 0
+	for _, leaf := range leaves {
+		value, upload := leafValue(leaf)
+		if !upload {
+			continue
+		}
+		hasPolicies := policies[leaf.Path] != ""
+		switch valueTier(value) {
+		case types.ParameterTierAdvanced:
+			advanced++
+		case types.ParameterTierIntelligentTiering:
+			// Intelligent-Tiering only promotes what needs Advanced
+			if len(value) > maxStandardValue || hasPolicies {
+				advanced++
+			}
+		}
+	}
+	if advanced == 0 {
+		return
+	}
+
+	cost := float64(advanced) * advancedMonthlyCost
+	if emit(event{Operation: "load", Result: resultPlanned, Message: "advanced tier", Data: map[string]interface{}{"advanced": advanced, "monthly_cost_usd": cost}}) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s %d parameter(s) will use the Advanced tier (about $%.2f per month)\n", icon(markWarn), advanced, cost)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestTierFlag(t *testing.T) {
+	defer func(tier string) { loadTier = tier }(loadTier)
+	small, large := "x", strings.Repeat("x", maxStandardValue+1)
+	tests := []struct {
+		tier                 string
+		wantErr              bool
+		standardOnly         bool
+		smallTier, largeTier types.ParameterTier
+	}{
+		{tierAuto, false, false, "", types.ParameterTierAdvanced},
+		{"standard", false, true, types.ParameterTierStandard, types.ParameterTierStandard},
+		{"Advanced", false, false, types.ParameterTierAdvanced, types.ParameterTierAdvanced},
+		{"intelligent-tiering", false, false, types.ParameterTierIntelligentTiering, types.ParameterTierIntelligentTiering},
+		{"", true, false, "", ""},
+		{"premium", true, false, "", ""},
+	}
+	for _, tt := range tests {
+		loadTier = tt.tier
+		if err := validateTierFlag(); (err != nil) != tt.wantErr {
+			t.Errorf("--tier %q: error = %v, want error %v", tt.tier, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got := standardTierOnly(); got != tt.standardOnly {
+			t.Errorf("--tier %s: standardTierOnly() = %v, want %v", tt.tier, got, tt.standardOnly)
+		}
+		if got := valueTier(small); got != tt.smallTier {
+			t.Errorf("--tier %s: small value tier %q, want %q", tt.tier, got, tt.smallTier)
+		}
+		if got := valueTier(large); got != tt.largeTier {
+			t.Errorf("--tier %s: large value tier %q, want %q", tt.tier, got, tt.largeTier)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+var trashPrefix string
+
+const (
+	defaultTrashPrefix = "/_trash"
+	trashStampLayout   = "20060102T150405Z"
+)
+
+// trashEntry is a parameter living at <trash prefix>/<stamp>/<original path>
+type trashEntry struct {
+	Name     string
+	Original string
+	Stamp    time.Time
+	Secure   bool
+}
+
+func normalizedTrashPrefix() string {
+	return "/" + strings.Trim(trashPrefix, "/")
+}
+
+func trashPath(stamp time.Time, original string) string {
+	return normalizedTrashPrefix() + "/" + stamp.UTC().Format(trashStampLayout) + "/" + strings.TrimPrefix(original, "/")
+}
+
+// checkTrashable fails when --soft is set and a trash path would break Parameter
+// Store limits: the prefix and timestamp add levels, so keys near the 15 level
+// limit can't be moved. It runs before anything is confirmed or written.
+func checkTrashable(names []string) error {
+	if !deleteSoft {
+		return nil
+	}
+	stamp := time.Now()
+	var tooDeep []string
+	for _, name := range names {
+		p := trashPath(stamp, name)
+		if strings.Count(p, "/") > maxHierarchyDepth || len(p) > maxNameLength {
+			tooDeep = append(tooDeep, name)
+		}
+	}
+	if len(tooDeep) == 0 {
+		return nil
+	}
+	for _, name := range tooDeep {
+		if !emit(event{Operation: "trash", Path: name, Result: resultError, ErrorCode: "TooDeep", Message: "trash path would exceed the name limits"}) {
+			fmt.Fprintf(os.Stderr, "%s %s\n", icon(markFail), style(roleName).Sprint(name))
+		}
+	}
+	levels := maxHierarchyDepth - strings.Count(trashPath(stamp, "/x"), "/") + 1
+	return fmt.Errorf("%d parameter(s) are too deep to move to %s, which leaves room for %d levels (delete them without --soft or use a shorter --trash-prefix)", len(tooDeep), normalizedTrashPrefix(), levels)
+}
+
+// trashParameters moves names under the trash prefix: each parameter is copied with its
+// metadata and tags to a timestamped path, then the original is deleted
+func trashParameters(names []string, client *ssm.Client) error {
+	params, err := collectSnapshotParams(names, client)
+	if err != nil {
+		return fmt.Errorf("failed to read parameters to move: %w", err)
+	}
+
+	stamp := time.Now()
+	var moved []string
+	for _, p := range params {
+		original := p.Name
+		p.Name = trashPath(stamp, original)
+		// Expiration or notification policies make no sense on trashed copies
+		p.Policies = ""
+		if err := putSnapshotParam(p, nil, false, client); err != nil {
+			emitError("trash", "move to trash", original, err)
+			continue
+		}
+		if !emit(event{Operation: "trash", Path: original, Type: p.Type, Result: resultOK, Data: map[string]string{"trash_path": p.Name}}) {
+			fmt.Printf("%s Moved %s → %s\n", icon(markTrash), original, style(roleMuted).Sprint(p.Name))
+		}
+		moved = append(moved, original)
+	}
+
+	if len(moved) > 0 {
+		deleteInBatches(moved, client)
+	}
+	return nil
+}
+
+// listTrash returns every well-formed entry under the trash prefix, oldest first
+func listTrash(client *ssm.Client) ([]trashEntry, error) {
+	root := normalizedTrashPrefix()
+	params, err := fetchAllParameterObjects(root, false, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []trashEntry
+	for name, p := range params {
+		rel := strings.TrimPrefix(name, root+"/")
+		stampPart, original, ok := strings.Cut(rel, "/")
+		if !ok {
+			continue
+		}
+		stamp, err := time.Parse(trashStampLayout, stampPart)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, trashEntry{
+			Name:     name,
+			Original: "/" + original,
+			Stamp:    stamp,
+			Secure:   p.Type == types.ParameterTypeSecureString,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Stamp.Equal(entries[j].Stamp) {
+			return entries[i].Stamp.Before(entries[j].Stamp)
+		}
+		return entries[i].Original < entries[j].Original
+	})
+	return entries, nil
+}
+
+// parseAge parses durations like "90m", "12h", "30d" or "2w"
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	unit := s[len(s)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.ParseFloat(s[:len(s)-1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		days := n
+		if unit == 'w' {
+			days *= 7
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 12h, 30d or 2w)", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckTrashable(t *testing.T) {
+	defer func(soft bool, prefix string) { deleteSoft, trashPrefix = soft, prefix }(deleteSoft, trashPrefix)
+	deep := func(levels int) string {
+		return strings.Repeat("/a", levels)
+	}
+	tests := []struct {
+		soft      bool
+		prefix    string
+		names     []string
+		wantError bool
+	}{
+		{true, defaultTrashPrefix, []string{deep(1), deep(13)}, false},
+		{true, defaultTrashPrefix, []string{deep(1), deep(14)}, true},
+		{true, defaultTrashPrefix, []string{deep(15)}, true},
+		{true, "/ops/trash", []string{deep(13)}, true},
+		{true, "/ops/trash", []string{deep(12)}, false},
+		{true, defaultTrashPrefix, []string{"/" + strings.Repeat("x", maxNameLength)}, true},
+		{false, defaultTrashPrefix, []string{deep(15)}, false},
+	}
+	for _, tt := range tests {
+		deleteSoft, trashPrefix = tt.soft, tt.prefix
+		err := checkTrashable(tt.names)
+		if (err != nil) != tt.wantError {
+			t.Errorf("checkTrashable(soft=%v, prefix=%s, %d names) error = %v, want error %v", tt.soft, tt.prefix, len(tt.names), err, tt.wantError)
+		}
+	}
+}
@@ -1,13 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	"github.com/fatih/color"
@@ -15,9 +17,16 @@ import (
 )
 
 var (
-	decryptValues bool
-	showValues    bool
-	treePrefix    string
+	decryptValues  bool
+	showValues     bool
+	treePrefix     string
+	treeDepth      int
+	treeCounts     bool
+	treeInclude    []string
+	treeExclude    []string
+	treeOnlySecure bool
+	treeFormat     string
+	treePolicies   bool
 )
 
 var treeCmd = &cobra.Command{
@@ -28,22 +37,23 @@ var treeCmd = &cobra.Command{
 		if treePrefix == "" {
 			return fmt.Errorf("--prefix is required")
 		}
-
-		//awsCfg, err := config.LoadDefaultConfig(context.TODO())
-		var cfgOpts []func(*config.LoadOptions) error
-		if awsRegion != "" {
-			cfgOpts = append(cfgOpts, config.WithRegion(awsRegion))
+		if err := validateTreeFormat(); err != nil {
+			return err
 		}
-		awsCfg, err := config.LoadDefaultConfig(ctx, cfgOpts...)
+
+		client, err := newSSMClient()
 		if err != nil {
-			return fmt.Errorf("failed to load AWS config: %w", err)
+			return err
 		}
-
-		client := ssm.NewFromConfig(awsCfg)
-		paramData, err := fetchAllParameterObjects(treePrefix, client)
+		paramData, err := fetchAllParameterObjects(treePrefix, decryptValues, client)
 		if err != nil {
 			return err
 		}
+		if treePolicies {
+			if err := addParameterPolicies(paramData, treePrefix, client); err != nil {
+				return err
+			}
+		}
 
 		paths := make([]string, 0, len(paramData))
 		for k := range paramData {
@@ -64,14 +74,38 @@ func init() {
 	treeCmd.Flags().BoolVarP(&decryptValues, "decrypt", "d", false, "Decrypt SecureString values (requires IAM permission)")
 	treeCmd.Flags().StringVarP(&treePrefix, "prefix", "p", "", "SSM path prefix to read from (e.g. /myapp) (required)")
 	treeCmd.Flags().BoolVarP(&showValues, "values", "v", false, "Show values alongside keys")
+	treeCmd.Flags().BoolVar(&treePolicies, "policies", false, "Show expiration and notification policies (needs ssm:DescribeParameters)")
+	addTreeFilterFlags(treeCmd)
+}
+
+// addTreeFilterFlags registers the display options shared by tree and yaml-tree
+func addTreeFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&treeDepth, "depth", "L", 0, "Collapse levels deeper than this into \"(N params)\" summaries (0 = unlimited)")
+	cmd.Flags().BoolVarP(&treeCounts, "counts", "c", false, "Show String vs SecureString counts per branch")
+	cmd.Flags().StringSliceVarP(&treeInclude, "include", "i", nil, "Only show keys matching these globs (relative to the root)")
+	cmd.Flags().StringSliceVarP(&treeExclude, "exclude", "x", nil, "Hide keys matching these globs (relative to the root)")
+	cmd.Flags().BoolVarP(&treeOnlySecure, "only-secure", "S", false, "Only show SecureString (or secret-like) keys")
+	cmd.Flags().StringVarP(&treeFormat, "format", "F", "text", "Output format: text, plain (no colors/emoji), json, markdown or dot")
+}
+
+func validateTreeFormat() error {
+	switch treeFormat {
+	case "text", "plain", "json", "markdown", "md", "dot":
+		return nil
+	}
+	return fmt.Errorf("unsupported --format %q (use text, plain, json, markdown or dot)", treeFormat)
 }
 
 type treeParam struct {
-	Type  types.ParameterType
-	Value string
+	Type         types.ParameterType
+	Value        string
+	Version      int64
+	LastModified *time.Time
+	DataType     string
+	Policies     []string
 }
 
-func fetchAllParameterObjects(prefix string, client *ssm.Client) (map[string]treeParam, error) {
+func fetchAllParameterObjects(prefix string, decrypt bool, client *ssm.Client) (map[string]treeParam, error) {
 	result := make(map[string]treeParam)
 	nextToken := aws.String("")
 
@@ -79,7 +113,7 @@ func fetchAllParameterObjects(prefix string, client *ssm.Client) (map[string]tre
 		input := &ssm.GetParametersByPathInput{
 			Path:           aws.String(prefix),
 			Recursive:      aws.Bool(true),
-			WithDecryption: aws.Bool(decryptValues),
+			WithDecryption: aws.Bool(decrypt),
 			NextToken:      nil,
 		}
 		if *nextToken != "" {
@@ -93,8 +127,11 @@ func fetchAllParameterObjects(prefix string, client *ssm.Client) (map[string]tre
 
 		for _, param := range out.Parameters {
 			result[*param.Name] = treeParam{
-				Type:  param.Type,
-				Value: *param.Value,
+				Type:         param.Type,
+				Value:        *param.Value,
+				Version:      param.Version,
+				LastModified: param.LastModifiedDate,
+				DataType:     aws.ToString(param.DataType),
 			}
 		}
 
@@ -107,16 +144,87 @@ func fetchAllParameterObjects(prefix string, client *ssm.Client) (map[string]tre
 	return result, nil
 }
 
-func printTree(paths []string, values map[string]treeParam, rootPrefix string) {
-	type node struct {
-		name     string
-		fullPath string
-		children map[string]*node
+// addParameterPolicies fills in the policies of params, which GetParametersByPath
+// doesn't return
+func addParameterPolicies(params map[string]treeParam, prefix string, client *ssm.Client) error {
+	var filters []types.ParameterStringFilter
+	if strings.Trim(prefix, "/") != "" {
+		filters = append(filters, types.ParameterStringFilter{
+			Key:    aws.String("Path"),
+			Option: aws.String("Recursive"),
+			Values: []string{"/" + strings.Trim(prefix, "/")},
+		})
+	}
+	metas, err := describeParameters(filters, client)
+	if err != nil {
+		return err
+	}
+	for _, m := range metas {
+		name := aws.ToString(m.Name)
+		if p, ok := params[name]; ok && len(m.Policies) > 0 {
+			p.Policies = describePolicies(m.Policies)
+			params[name] = p
+		}
 	}
+	return nil
+}
 
-	root := &node{name: "/", fullPath: rootPrefix, children: make(map[string]*node)}
+// treeNode is one level of the parameter hierarchy as rendered by printTree
+type treeNode struct {
+	name      string
+	fullPath  string
+	children  map[string]*treeNode
+	strings   int
+	secures   int
+	collapsed bool
+}
+
+// sortedChildren returns the children in display order
+func (n *treeNode) sortedChildren() []*treeNode {
+	keys := make([]string, 0, len(n.children))
+	for k := range n.children {
+		keys = append(keys, k)
+	}
+	//sort.Strings(keys)
+	sort.SliceStable(keys, func(i, j int) bool {
+		//return len(strings.Split(keys[i], "/")) < len(strings.Split(keys[j], "/"))
+		depthI := len(strings.Split(keys[i], "/"))
+		depthJ := len(strings.Split(keys[j], "/"))
+		if depthI != depthJ {
+			return depthI < depthJ
+		}
+		return keys[i] < keys[j] // fallback to alphabetical within same depth
+	})
+
+	children := make([]*treeNode, 0, len(keys))
+	for _, k := range keys {
+		children = append(children, n.children[k])
+	}
+	return children
+}
+
+func (n *treeNode) summary() string {
+	s := fmt.Sprintf("%d params", n.strings+n.secures)
+	if n.secures > 0 {
+		s += fmt.Sprintf(", %d %s", n.secures, icon(markSecure))
+	}
+	return s
+}
+
+// buildTree turns relative paths into a hierarchy, applying the --include/--exclude,
+// --only-secure and --depth options and counting String vs SecureString leaves per branch
+func buildTree(paths []string, values map[string]treeParam, rootPrefix string) *treeNode {
+	root := &treeNode{name: "/", fullPath: rootPrefix, children: make(map[string]*treeNode)}
 
 	for _, path := range paths {
+		fullPath := strings.TrimSuffix(rootPrefix, "/") + "/" + path
+		if !matchesTreeFilters(path, treeInclude, treeExclude) {
+			continue
+		}
+		if treeOnlySecure && values[fullPath].Type != types.ParameterTypeSecureString {
+			continue
+		}
+
 		parts := strings.Split(path, "/")
 		current := root
 		currentPath := strings.TrimSuffix(rootPrefix, "/")
@@ -124,41 +232,144 @@ func printTree(paths []string, values map[string]treeParam, rootPrefix string) {
 		for _, part := range parts {
 			currentPath += "/" + part
 			if current.children[part] == nil {
-				current.children[part] = &node{
+				current.children[part] = &treeNode{
 					name:     part,
 					fullPath: currentPath,
-					children: make(map[string]*node),
+					children: make(map[string]*treeNode),
 				}
 			}
 			current = current.children[part]
 		}
 	}
 
-	var walk func(n *node, prefix string, last bool)
-	walk = func(n *node, prefix string, last bool) {
-		connector := "├── "
-		if last {
-			connector = "└── "
+	var count func(n *treeNode, depth int)
+	count = func(n *treeNode, depth int) {
+		if param, ok := values[n.fullPath]; ok && n != root {
+			if param.Type == types.ParameterTypeSecureString {
+				n.secures++
+			} else {
+				n.strings++
+			}
+		}
+		for _, c := range n.children {
+			count(c, depth+1)
+			n.strings += c.strings
+			n.secures += c.secures
+		}
+		// Collapse everything below --depth into a summary
+		if treeDepth > 0 && depth >= treeDepth && len(n.children) > 0 {
+			n.collapsed = true
+			n.children = make(map[string]*treeNode)
 		}
+	}
+	count(root, 0)
 
-		label := n.name
-		// Color numbers differently (e.g., list indices)
-		if _, err := strconv.Atoi(n.name); err == nil {
-			label = color.New(color.FgYellow).Sprint(label)
+	return root
+}
+
+func printTree(paths []string, values map[string]treeParam, rootPrefix string) {
+	root := buildTree(paths, values, rootPrefix)
+	if jsonOutput() {
+		emitTree(root, values)
+		return
+	}
+
+	switch treeFormat {
+	case "json":
+		printTreeJSON(root, values)
+	case "markdown", "md":
+		printTreeMarkdown(root, values)
+	case "dot":
+		printTreeDOT(root, values)
+	default:
+		printTreeText(root, values, treeFormat == "plain")
+	}
+}
+
+// emitTree reports every leaf (and every branch collapsed by --depth) as an event
+func emitTree(n *treeNode, values map[string]treeParam) {
+	for _, c := range n.sortedChildren() {
+		if c.collapsed {
+			emit(event{Operation: "tree", Path: c.fullPath, Result: resultOK, Data: map[string]int{"strings": c.strings, "secure_strings": c.secures}})
+			continue
 		}
+		if param, ok := values[c.fullPath]; ok {
+			e := event{Operation: "tree", Path: c.fullPath, Type: string(param.Type), Result: resultOK}
+			data := map[string]interface{}{}
+			if showValues {
+				data["value"] = param.Value
+			}
+			if len(param.Policies) > 0 {
+				data["policies"] = param.Policies
+			}
+			if len(data) > 0 {
+				e.Data = data
+			}
+			emit(e)
+		}
+		emitTree(c, values)
+	}
+}
 
-		// Apply secure string or standard coloring
-		if param, ok := values[n.fullPath]; ok {
-			// Format base label with SecureString icon if needed
-			if param.Type == types.ParameterTypeSecureString {
-				label = color.New(color.FgCyan).Sprintf("%s 🔒", label)
-			} else {
-				label = color.New(color.FgWhite).Sprint(label)
+// printTreeText draws the tree with box characters, colors and emoji, or with
+// ASCII only when plain is set
+func printTreeText(root *treeNode, values map[string]treeParam, plain bool) {
+	branch, lastBranch, pipe := "├── ", "└── ", "│   "
+	if plain {
+		branch, lastBranch, pipe = "|-- ", "`-- ", "|   "
+	}
+
+	var walk func(n *treeNode, prefix string, last bool)
+	walk = func(n *treeNode, prefix string, last bool) {
+		connector := branch
+		if last {
+			connector = lastBranch
+		}
+
+		var label string
+		if plain {
+			label = n.name
+			if param, ok := values[n.fullPath]; ok {
+				if param.Type == types.ParameterTypeSecureString {
+					label += " [secure]"
+				}
+				if showValues {
+					label += " = " + param.Value
+				}
+				if len(param.Policies) > 0 {
+					label += " [" + strings.Join(param.Policies, ", ") + "]"
+				}
+			}
+			if n.collapsed {
+				label += fmt.Sprintf(" (%d params, %d secure)", n.strings+n.secures, n.secures)
+			} else if treeCounts && len(n.children) > 0 {
+				label += fmt.Sprintf(" [%d String, %d SecureString]", n.strings, n.secures)
+			}
+		} else {
+			label = n.name
+			// Color numbers differently (e.g., list indices)
+			if _, err := strconv.Atoi(n.name); err == nil {
+				label = style(roleIndex).Sprint(label)
 			}
 
-			// Append value if requested
-			if showValues {
-				label += fmt.Sprintf(" = %s", color.New(color.FgHiBlack).Sprint(param.Value))
+			// Apply secure string or standard coloring
+			if param, ok := values[n.fullPath]; ok {
+				// Format base label with SecureString icon if needed
+				label = styleLabel(label, param.Type)
+
+				// Append value if requested
+				if showValues {
+					label += fmt.Sprintf(" = %s", style(roleMuted).Sprint(param.Value))
+				}
+				if len(param.Policies) > 0 {
+					label += style(roleWarning).Sprintf(" [%s]", strings.Join(param.Policies, ", "))
+				}
+			}
+
+			if n.collapsed {
+				label += style(roleMuted).Sprintf(" (%s)", n.summary())
+			} else if treeCounts && len(n.children) > 0 {
+				label += style(roleMuted).Sprintf(" [%d String, %d SecureString]", n.strings, n.secures)
 			}
 		}
 
@@ -166,30 +377,172 @@ func printTree(paths []string, values map[string]treeParam, rootPrefix string) {
 			fmt.Printf("%s%s%s\n", prefix, connector, label)
 		}
 
-		keys := make([]string, 0, len(n.children))
-		for k := range n.children {
-			keys = append(keys, k)
-		}
-		//sort.Strings(keys)
-		sort.SliceStable(keys, func(i, j int) bool {
-			//return len(strings.Split(keys[i], "/")) < len(strings.Split(keys[j], "/"))
-			depthI := len(strings.Split(keys[i], "/"))
-			depthJ := len(strings.Split(keys[j], "/"))
-			if depthI != depthJ {
-				return depthI < depthJ
-			}
-			return keys[i] < keys[j] // fallback to alphabetical within same depth
-		})
-
 		newPrefix := prefix
 		if n.name != "/" {
-			newPrefix += map[bool]string{true: "    ", false: "│   "}[last]
+			newPrefix += map[bool]string{true: "    ", false: pipe}[last]
 		}
 
-		for i, k := range keys {
-			walk(n.children[k], newPrefix, i == len(keys)-1)
+		children := n.sortedChildren()
+		for i, c := range children {
+			walk(c, newPrefix, i == len(children)-1)
 		}
 	}
 
 	walk(root, "", true)
+
+	if treeCounts {
+		if plain {
+			fmt.Printf("\n%d params, %d secure\n", root.strings+root.secures, root.secures)
+		} else {
+			fmt.Printf("\n%s\n", root.summary())
+		}
+	}
+}
+
+type treeJSONNode struct {
+	Name         string          `json:"name"`
+	Path         string          `json:"path"`
+	Type         string          `json:"type,omitempty"`
+	Value        *string         `json:"value,omitempty"`
+	Version      int64           `json:"version,omitempty"`
+	LastModified *time.Time      `json:"last_modified,omitempty"`
+	DataType     string          `json:"data_type,omitempty"`
+	Policies     []string        `json:"policies,omitempty"`
+	Strings      int             `json:"string_count"`
+	Secures      int             `json:"secure_count"`
+	Collapsed    bool            `json:"collapsed,omitempty"`
+	Children     []*treeJSONNode `json:"children,omitempty"`
+}
+
+func printTreeJSON(root *treeNode, values map[string]treeParam) {
+	var convert func(n *treeNode) *treeJSONNode
+	convert = func(n *treeNode) *treeJSONNode {
+		out := &treeJSONNode{
+			Name:      n.name,
+			Path:      n.fullPath,
+			Strings:   n.strings,
+			Secures:   n.secures,
+			Collapsed: n.collapsed,
+		}
+		if param, ok := values[n.fullPath]; ok {
+			out.Type = string(param.Type)
+			out.Version = param.Version
+			out.LastModified = param.LastModified
+			out.DataType = param.DataType
+			out.Policies = param.Policies
+			if showValues {
+				v := param.Value
+				out.Value = &v
+			}
+		}
+		for _, c := range n.sortedChildren() {
+			out.Children = append(out.Children, convert(c))
+		}
+		return out
+	}
+
+	root.name = root.fullPath
+	if root.name == "" {
+		root.name, root.fullPath = "/", "/"
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(convert(root))
+}
+
+func printTreeMarkdown(root *treeNode, values map[string]treeParam) {
+	var walk func(n *treeNode, depth int)
+	walk = func(n *treeNode, depth int) {
+		line := fmt.Sprintf("%s- ", strings.Repeat("  ", depth))
+		param, isParam := values[n.fullPath]
+		if isParam {
+			line += "`" + n.name + "`"
+			if param.Type == types.ParameterTypeSecureString {
+				line += " " + icon(markSecure)
+			}
+			if showValues {
+				line += " = `" + strings.ReplaceAll(param.Value, "`", "'") + "`"
+			}
+		} else {
+			line += "**" + n.name + "**"
+		}
+		if n.collapsed {
+			line += fmt.Sprintf(" _(%s)_", n.summary())
+		} else if treeCounts && len(n.children) > 0 {
+			line += fmt.Sprintf(" _(%d String, %d SecureString)_", n.strings, n.secures)
+		}
+		fmt.Println(line)
+
+		for _, c := range n.sortedChildren() {
+			walk(c, depth+1)
+		}
+	}
+
+	for _, c := range root.sortedChildren() {
+		walk(c, 0)
+	}
+}
+
+func printTreeDOT(root *treeNode, values map[string]treeParam) {
+	quote := func(s string) string {
+		return strconv.Quote(s)
+	}
+	rootID := root.fullPath
+	if rootID == "" {
+		rootID = "/"
+	}
+
+	fmt.Println("digraph ssm {")
+	fmt.Println("  rankdir=LR;")
+	fmt.Println("  node [shape=box, style=rounded, fontname=\"Helvetica\"];")
+	fmt.Printf("  %s [label=%s, shape=folder];\n", quote(rootID), quote(rootID))
+
+	var walk func(n *treeNode, parentID string)
+	walk = func(n *treeNode, parentID string) {
+		label := n.name
+		attrs := ""
+		if param, ok := values[n.fullPath]; ok {
+			if param.Type == types.ParameterTypeSecureString {
+				label += " " + icon(markSecure)
+				attrs = ", style=\"rounded,filled\", fillcolor=\"lightblue\""
+			}
+			if showValues {
+				label += "\n" + param.Value
+			}
+		} else {
+			attrs = ", shape=folder"
+		}
+		if n.collapsed {
+			label += fmt.Sprintf("\n(%s)", n.summary())
+		} else if treeCounts && len(n.children) > 0 {
+			label += fmt.Sprintf("\n(%d String, %d SecureString)", n.strings, n.secures)
+		}
+
+		fmt.Printf("  %s [label=%s%s];\n", quote(n.fullPath), quote(label), attrs)
+		fmt.Printf("  %s -> %s;\n", quote(parentID), quote(n.fullPath))
+		for _, c := range n.sortedChildren() {
+			walk(c, n.fullPath)
+		}
+	}
+
+	for _, c := range root.sortedChildren() {
+		walk(c, rootID)
+	}
+	fmt.Println("}")
+}
+
+// styleLabel applies the tree coloring to a parameter label: cyan with a lock for
+// SecureString, white otherwise
+func styleLabel(label string, paramType types.ParameterType) string {
+	if paramType == types.ParameterTypeSecureString {
+		return labelColor(paramType).Sprintf("%s %s", label, icon(markSecure))
+	}
+	return labelColor(paramType).Sprint(label)
+}
+
+func labelColor(paramType types.ParameterType) *color.Color {
+	if paramType == types.ParameterTypeSecureString {
+		return style(roleSecure)
+	}
+	return style(roleString)
 }
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// treeFixture is a small prefix with nested Strings and SecureStrings
+func treeFixture() ([]string, map[string]treeParam) {
+	values := map[string]treeParam{
+		"/app/db/host":          {Type: types.ParameterTypeString, Value: "db.internal"},
+		"/app/db/password":      {Type: types.ParameterTypeSecureString, Value: "s3cret"},
+		"/app/api/keys/primary": {Type: types.ParameterTypeSecureString, Value: "k1"},
+		"/app/api/url":          {Type: types.ParameterTypeString, Value: "https://api"},
+		"/app/name":             {Type: types.ParameterTypeString, Value: "app"},
+	}
+	var paths []string
+	for name := range values {
+		paths = append(paths, strings.TrimPrefix(name, "/app/"))
+	}
+	sort.Strings(paths)
+	return paths, values
+}
+
+// flattenTree lists every node below root as "path strings/secures", with a
+// trailing "+" for branches collapsed by --depth
+func flattenTree(root *treeNode) []string {
+	var lines []string
+	var walk func(n *treeNode)
+	walk = func(n *treeNode) {
+		for _, c := range n.sortedChildren() {
+			line := fmt.Sprintf("%s %d/%d", c.fullPath, c.strings, c.secures)
+			if c.collapsed {
+				line += "+"
+			}
+			lines = append(lines, line)
+			walk(c)
+		}
+	}
+	walk(root)
+	sort.Strings(lines)
+	return lines
+}
+
+func TestBuildTree(t *testing.T) {
+	tests := []struct {
+		name             string
+		depth            int
+		include, exclude []string
+		onlySecure       bool
+		want             []string
+	}{
+		{"all", 0, nil, nil, false, []string{
+			"/app/api 1/1", "/app/api/keys 0/1", "/app/api/keys/primary 0/1", "/app/api/url 1/0",
+			"/app/db 1/1", "/app/db/host 1/0", "/app/db/password 0/1", "/app/name 1/0",
+		}},
+		{"depth 1", 1, nil, nil, false, []string{"/app/api 1/1+", "/app/db 1/1+", "/app/name 1/0"}},
+		{"depth 2", 2, nil, nil, false, []string{
+			"/app/api 1/1", "/app/api/keys 0/1+", "/app/api/url 1/0",
+			"/app/db 1/1", "/app/db/host 1/0", "/app/db/password 0/1", "/app/name 1/0",
+		}},
+		{"include branch", 0, []string{"db"}, nil, false, []string{"/app/db 1/1", "/app/db/host 1/0", "/app/db/password 0/1"}},
+		{"exclude glob", 0, nil, []string{"**/p*"}, false, []string{"/app/api 1/0", "/app/api/url 1/0", "/app/db 1/0", "/app/db/host 1/0", "/app/name 1/0"}},
+		{"only secure", 0, nil, nil, true, []string{
+			"/app/api 0/1", "/app/api/keys 0/1", "/app/api/keys/primary 0/1", "/app/db 0/1", "/app/db/password 0/1",
+		}},
+	}
+	defer func(d int, i, x []string, s bool) { treeDepth, treeInclude, treeExclude, treeOnlySecure = d, i, x, s }(treeDepth, treeInclude, treeExclude, treeOnlySecure)
+	paths, values := treeFixture()
+	for _, tt := range tests {
+		treeDepth, treeInclude, treeExclude, treeOnlySecure = tt.depth, tt.include, tt.exclude, tt.onlySecure
+		root := buildTree(paths, values, "/app")
+		if got := flattenTree(root); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTreeFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		depth  int
+		want   string
+	}{
+		{"plain", 0, "|-- api\n" +
+			"|   |-- keys\n" +
+			"|   |   `-- primary [secure] = k1\n" +
+			"|   `-- url = https://api\n" +
+			"|-- db\n" +
+			"|   |-- host = db.internal\n" +
+			"|   `-- password [secure] = s3cret\n" +
+			"`-- name = app\n"},
+		{"plain", 1, "|-- api (2 params, 1 secure)\n" +
+			"|-- db (2 params, 1 secure)\n" +
+			"`-- name = app\n"},
+		{"markdown", 1, "- **api** _(2 params, 1 🔒)_\n" +
+			"- **db** _(2 params, 1 🔒)_\n" +
+			"- `name` = `app`\n"},
+		{"dot", 1, "digraph ssm {\n" +
+			"  rankdir=LR;\n" +
+			"  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n" +
+			"  \"/app\" [label=\"/app\", shape=folder];\n" +
+			"  \"/app/api\" [label=\"api\\n(2 params, 1 🔒)\", shape=folder];\n" +
+			"  \"/app\" -> \"/app/api\";\n" +
+			"  \"/app/db\" [label=\"db\\n(2 params, 1 🔒)\", shape=folder];\n" +
+			"  \"/app\" -> \"/app/db\";\n" +
+			"  \"/app/name\" [label=\"name\\napp\"];\n" +
+			"  \"/app\" -> \"/app/name\";\n" +
+			"}\n"},
+		{"json", 1, `{
+  "name": "/app",
+  "path": "/app",
+  "string_count": 3,
+  "secure_count": 2,
+  "children": [
+    {
+      "name": "api",
+      "path": "/app/api",
+      "string_count": 1,
+      "secure_count": 1,
+      "collapsed": true
+    },
+    {
+      "name": "db",
+      "path": "/app/db",
+      "string_count": 1,
+      "secure_count": 1,
+      "collapsed": true
+    },
+    {
+      "name": "name",
+      "path": "/app/name",
+      "type": "String",
+      "value": "app",
+      "string_count": 1,
+      "secure_count": 0
+    }
+  ]
+}
+`},
+	}
+	defer func(f string, d int, v bool) { treeFormat, treeDepth, showValues = f, d, v }(treeFormat, treeDepth, showValues)
+	showValues = true
+	paths, values := treeFixture()
+	for _, tt := range tests {
+		treeFormat, treeDepth = tt.format, tt.depth
+		got := captureStdout(t, func() { printTree(paths, values, "/app") })
+		if got != tt.want {
+			t.Errorf("--format %s --depth %d:\ngot\n%s\nwant\n%s", tt.format, tt.depth, got, tt.want)
+		}
+	}
+}
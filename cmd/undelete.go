@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	undeletePrefix    string
+	undeleteFrom      string
+	undeleteInclude   []string
+	undeleteExclude   []string
+	undeleteYes       bool
+	undeleteOverwrite bool
+)
+
+var undeleteCmd = &cobra.Command{
+	Use:     "undelete",
+	Short:   "Move soft-deleted parameters back from the trash prefix",
+	Aliases: []string{"ud", "undel"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var from time.Time
+		if undeleteFrom != "" {
+			var err error
+			from, err = time.Parse(trashStampLayout, undeleteFrom)
+			if err != nil {
+				return fmt.Errorf("invalid --from %q (expected a trash timestamp like 20261018T101500Z)", undeleteFrom)
+			}
+		}
+
+		client, err := newSSMClient()
+		if err != nil {
+			return err
+		}
+
+		entries, err := listTrash(client)
+		if err != nil {
+			return err
+		}
+
+		// Keep only the newest trashed copy of each original, unless --from pins a run
+		prefix := "/" + strings.Trim(undeletePrefix, "/")
+		latest := make(map[string]trashEntry)
+		var originals []string
+		for _, e := range entries {
+			if undeletePrefix != "" && e.Original != prefix && !strings.HasPrefix(e.Original, prefix+"/") {
+				continue
+			}
+			if !from.IsZero() && !e.Stamp.Equal(from) {
+				continue
+			}
+			if !matchesFilters(e.Original, undeleteInclude, undeleteExclude) {
+				continue
+			}
+			if _, ok := latest[e.Original]; !ok {
+				originals = append(originals, e.Original)
+			}
+			latest[e.Original] = e
+		}
+
+		if len(originals) == 0 {
+			fmt.Fprintln(humanOut(), "Nothing to undelete.")
+			return nil
+		}
+
+		fmt.Fprintf(humanOut(), "The following %d parameters will be moved back from %s:\n", len(originals), normalizedTrashPrefix())
+		for _, name := range originals {
+			e := latest[name]
+			if emit(event{Operation: "undelete", Path: name, Result: resultPlanned, Data: map[string]string{"trash_path": e.Name}}) {
+				continue
+			}
+			lockIcon := ""
+			if e.Secure {
+				lockIcon = " " + icon(markSecure)
+			}
+			fmt.Printf("%s%s %s\n", style(roleItem).Sprint(name), lockIcon, style(roleMuted).Sprintf("(deleted %s)", e.Stamp.Local().Format("2006-01-02 15:04:05")))
+		}
+
+		if !undeleteYes && !confirm("Continue? (y/N): ", false) {
+			abort("undelete")
+			return nil
+		}
+
+		trashNames := make([]string, 0, len(originals))
+		for _, name := range originals {
+			trashNames = append(trashNames, latest[name].Name)
+		}
+		params, err := collectSnapshotParams(trashNames, client)
+		if err != nil {
+			return fmt.Errorf("failed to read trashed parameters: %w", err)
+		}
+
+		byTrashName := make(map[string]string, len(originals))
+		for _, name := range originals {
+			byTrashName[latest[name].Name] = name
+		}
+
+		var restored []string
+		for _, p := range params {
+			trashName := p.Name
+			p.Name = byTrashName[trashName]
+			if err := putSnapshotParam(p, nil, undeleteOverwrite, client); err != nil {
+				emitError("undelete", "undelete", p.Name, err)
+				continue
+			}
+			if !emit(event{Operation: "undelete", Path: p.Name, Type: p.Type, Result: resultOK}) {
+				fmt.Printf("%s Restored %s\n", icon(markRestored), p.Name)
+			}
+			restored = append(restored, trashName)
+		}
+
+		if len(restored) > 0 {
+			deleteInBatches(restored, client)
+		}
+		return nil
+	},
+}
+
+func init() {
+	undeleteCmd.Flags().StringVarP(&undeletePrefix, "prefix", "p", "", "Only undelete parameters originally under this prefix")
+	undeleteCmd.Flags().StringVar(&undeleteFrom, "from", "", "Only undelete entries trashed at this timestamp (e.g. 20261018T101500Z)")
+	undeleteCmd.Flags().StringSliceVarP(&undeleteInclude, "include", "i", nil, "Only undelete original paths matching these globs")
+	undeleteCmd.Flags().StringSliceVarP(&undeleteExclude, "exclude", "x", nil, "Skip original paths matching these globs")
+	undeleteCmd.Flags().BoolVarP(&undeleteYes, "yes", "y", false, "Skip confirmation prompt")
+	undeleteCmd.Flags().BoolVarP(&undeleteOverwrite, "overwrite", "o", false, "Overwrite parameters that have been re-created since")
+	undeleteCmd.Flags().StringVar(&trashPrefix, "trash-prefix", defaultTrashPrefix, "SSM prefix holding soft-deleted parameters")
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	validatePrefix  string
+	onNull          string
+	nullPlaceholder string
+)
+
+// --on-null choices for null and empty YAML values, which Parameter Store can't store
+const (
+	onNullError       = "error"
+	onNullSkip        = "skip"
+	onNullPlaceholder = "placeholder"
+)
+
+// Parameter Store limits checked before anything is written
+const (
+	maxNameLength     = 2048
+	maxHierarchyDepth = 15
+	maxStandardValue  = 4096
+	// maxDescriptionLength applies to descriptions taken from YAML comments
+	maxDescriptionLength = 1024
+)
+
+// validNameSegment is what Parameter Store accepts between the slashes of a name
+var validNameSegment = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+var validateCmd = &cobra.Command{
+	Use:     "validate <file.yaml>...",
+	Short:   "Check YAML files against Parameter Store naming and size rules",
+	Long:    "Check every flattened path and value of the YAML files against Parameter Store rules (allowed characters, name length, hierarchy depth, value size, empty and null values) and report all problems with their line numbers.",
+	Aliases: []string{"lint", "v"},
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateNullFlags(); err != nil {
+			return err
+		}
+		if err := validateTierFlag(); err != nil {
+			return err
+		}
+		total := 0
+		for _, file := range args {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			problems, err := validateYAML(raw, validatePrefix)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			reportProblems(file, problems)
+			total += len(problems)
+		}
+		if total > 0 {
+			return fmt.Errorf("%d problem(s) found", total)
+		}
+		fmt.Fprintf(humanOut(), "%s %d file(s) valid\n", icon(markOK), len(args))
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.Flags().StringVarP(&validatePrefix, "prefix", "p", "", "SSM path prefix the files will be loaded under (counts towards length and depth)")
+	addNullFlags(validateCmd)
+	addTierFlag(validateCmd)
+}
+
+// addNullFlags registers --on-null and --null-placeholder, shared by load and validate
+func addNullFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&onNull, "on-null", onNullError, "What to do with null and empty values: error, skip or placeholder")
+	cmd.Flags().StringVar(&nullPlaceholder, "null-placeholder", "null", "Value uploaded for null and empty values with --on-null placeholder")
+}
+
+func validateNullFlags() error {
+	switch onNull {
+	case onNullError, onNullSkip:
+		return nil
+	case onNullPlaceholder:
+		if nullPlaceholder == "" {
+			return fmt.Errorf("--null-placeholder can't be empty")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported --on-null %q (use error, skip or placeholder)", onNull)
+}
+
+// validateProblem is one leaf that Parameter Store would reject
+type validateProblem struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// validateYAML checks every leaf of a YAML document as it would be uploaded below prefix
+func validateYAML(raw []byte, prefix string) ([]validateProblem, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	root := ""
+	if strings.Trim(prefix, "/") != "" {
+		root = "/" + strings.Trim(prefix, "/")
+	}
+
+	var problems []validateProblem
+	walkYAMLLeaves(&doc, root, func(path string, key, leaf *yaml.Node) {
+		for _, msg := range validateLeaf(path, leaf) {
+			problems = append(problems, validateProblem{Path: path, Line: leaf.Line, Message: msg})
+		}
+		if desc := leafComment(key, leaf); !noDescriptions && len(desc) > maxDescriptionLength {
+			msg := fmt.Sprintf("comment is %d characters long, the description limit is %d", len(desc), maxDescriptionLength)
+			problems = append(problems, validateProblem{Path: path, Line: leaf.Line, Message: msg})
+		}
+	})
+	return problems, nil
+}
+
+func validateLeaf(path string, leaf *yaml.Node) []string {
+	var msgs []string
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, s := range segments {
+		if s == "" {
+			msgs = append(msgs, "empty path segment")
+			continue
+		}
+		if !validNameSegment.MatchString(s) {
+			msgs = append(msgs, fmt.Sprintf("segment %q has characters other than letters, digits, '.', '-' and '_'", s))
+		}
+	}
+	if first := strings.ToLower(segments[0]); strings.HasPrefix(first, "aws") || strings.HasPrefix(first, "ssm") {
+		msgs = append(msgs, "names can't start with \"aws\" or \"ssm\"")
+	}
+	if len(path) > maxNameLength {
+		msgs = append(msgs, fmt.Sprintf("name is %d characters long, the limit is %d", len(path), maxNameLength))
+	}
+	if len(segments) > maxHierarchyDepth {
+		msgs = append(msgs, fmt.Sprintf("name has %d levels, the limit is %d", len(segments), maxHierarchyDepth))
+	}
+
+	switch {
+	case onNull != onNullError && (isNullNode(leaf) || leaf.Value == ""):
+		// handled by --on-null
+	case isNullNode(leaf):
+		msgs = append(msgs, "value is null (use --on-null skip or placeholder)")
+	case leaf.Value == "":
+		msgs = append(msgs, "value is empty, Parameter Store rejects empty values (use --on-null skip or placeholder)")
+	case len(leaf.Value) > maxAdvancedValue:
+		msgs = append(msgs, fmt.Sprintf("value is %d bytes, the Advanced tier limit is %d", len(leaf.Value), maxAdvancedValue))
+	case len(leaf.Value) > maxStandardValue && standardTierOnly():
+		msgs = append(msgs, fmt.Sprintf("value is %d bytes, the Standard tier limit is %d (use --tier auto or advanced)", len(leaf.Value), maxStandardValue))
+	}
+	return msgs
+}
+
+// reportProblems prints problems with their file and line, or emits them in json mode
+func reportProblems(file string, problems []validateProblem) {
+	for _, p := range problems {
+		if emit(event{Operation: "validate", Path: p.Path, Result: resultError, ErrorCode: "ValidationError", Message: p.Message, Data: map[string]interface{}{"file": file, "line": p.Line}}) {
+			continue
+		}
+		location := file
+		if p.Line > 0 {
+			location = fmt.Sprintf("%s:%d", file, p.Line)
+		}
+		fmt.Fprintf(os.Stderr, "%s %s %s: %s\n", icon(markFail), style(roleMuted).Sprint(location), style(roleName).Sprint(p.Path), style(roleError).Sprint(p.Message))
+	}
+}
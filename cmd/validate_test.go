@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateYAML(t *testing.T) {
+	deep := "a:\n"
+	for i := 0; i < maxHierarchyDepth; i++ {
+		deep += strings.Repeat("  ", i+1) + fmt.Sprintf("l%d:\n", i)
+	}
+	deep += strings.Repeat("  ", maxHierarchyDepth+1) + "leaf: x\n"
+
+	tests := []struct {
+		name   string
+		doc    string
+		prefix string
+		want   []string // "path:line|message part"
+	}{
+		{"valid", "db:\n  host: db.internal\n  port: 5432\nlist:\n  - a\n", "/app", nil},
+		{"bad characters", "db:\n  \"host name\": x\n  ok_key-1.2: y\n", "/app", []string{`/app/db/host name:2|segment "host name"`}},
+		{"reserved first segment", "aws_key: x\nSSM: y\nmy_aws: z\n", "", []string{`/aws_key:1|can't start with "aws"`, `/SSM:2|can't start with "aws"`}},
+		{"reserved via prefix", "key: x\n", "/awsome", []string{`/awsome/key:1|can't start with "aws"`}},
+		{"too deep", deep, "", []string{fmt.Sprintf("/a/l0/l1/l2/l3/l4/l5/l6/l7/l8/l9/l10/l11/l12/l13/l14/leaf:%d|name has %d levels", maxHierarchyDepth+2, maxHierarchyDepth+2)}},
+		{"too long", "k: x\n", "/" + strings.Repeat("p", maxNameLength), []string{fmt.Sprintf("/%s/k:1|name is %d characters", strings.Repeat("p", maxNameLength), maxNameLength+3)}},
+		{"value too large", "big: " + strings.Repeat("x", maxAdvancedValue+1) + "\n", "/app", []string{fmt.Sprintf("/app/big:1|value is %d bytes", maxAdvancedValue+1)}},
+		{"null and empty", "a: ~\nb: \"\"\n", "/app", []string{"/app/a:1|value is null", "/app/b:2|value is empty"}},
+	}
+	for _, tt := range tests {
+		problems, err := validateYAML([]byte(tt.doc), tt.prefix)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(problems) != len(tt.want) {
+			t.Errorf("%s: got %d problems %+v, want %d", tt.name, len(problems), problems, len(tt.want))
+			continue
+		}
+		for i, p := range problems {
+			where, text, _ := strings.Cut(tt.want[i], "|")
+			if got := fmt.Sprintf("%s:%d", p.Path, p.Line); got != where || !strings.Contains(p.Message, text) {
+				t.Errorf("%s: got %s %q, want %s containing %q", tt.name, got, p.Message, where, text)
+			}
+		}
+	}
+}
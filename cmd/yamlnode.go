@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlMetaKey is the top-level key holding aws-ssm settings (such as parameter
+// policies) inside a YAML file. It is never uploaded as parameters.
+const yamlMetaKey = "x-aws-ssm"
+
+// walkYAMLLeaves calls fn for every scalar leaf below node with its SSM style path,
+// so callers can report line numbers.
+// key is the mapping key of the leaf, or nil for sequence items.
+// Aliases are followed and "<<" merge keys are resolved like a decoder does (see
+// mappingEntries), so a key defined next to the merge wins over the merged one.
+func walkYAMLLeaves(node *yaml.Node, prefix string, fn func(path string, key, leaf *yaml.Node)) {
+	root := strings.TrimSuffix(prefix, "/")
+	var walk func(n *yaml.Node, path string, key *yaml.Node)
+	walk = func(n *yaml.Node, path string, key *yaml.Node) {
+		switch n.Kind {
+		case yaml.DocumentNode:
+			for _, c := range n.Content {
+				walk(c, path, nil)
+			}
+		case yaml.AliasNode:
+			walk(n.Alias, path, key)
+		case yaml.MappingNode:
+			for _, e := range mappingEntries(n) {
+				if path == root && e.key.Value == yamlMetaKey {
+					continue
+				}
+				walk(e.value, path+"/"+e.key.Value, e.key)
+			}
+		case yaml.SequenceNode:
+			for i, c := range n.Content {
+				walk(c, fmt.Sprintf("%s/%d", path, i), nil)
+			}
+		case yaml.ScalarNode:
+			fn(path, key, n)
+		}
+	}
+	walk(node, root, nil)
+}
+
+type mappingEntry struct {
+	key, value *yaml.Node
+}
+
+// mappingEntries lists the keys of a mapping with "<<" merges applied: merged keys
+// take the place of the merge key, keys of the mapping itself win over merged
+// ones, and with "<<: [*a, *b]" the earlier source wins
+func mappingEntries(n *yaml.Node) []mappingEntry {
+	local := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if !isMergeKey(n.Content[i]) {
+			local[n.Content[i].Value] = true
+		}
+	}
+
+	var entries []mappingEntry
+	seen := make(map[string]bool)
+	add := func(e mappingEntry) {
+		if !seen[e.key.Value] {
+			seen[e.key.Value] = true
+			entries = append(entries, e)
+		}
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if !isMergeKey(key) {
+			add(mappingEntry{key, value})
+			continue
+		}
+		for _, src := range mergeSources(value) {
+			for _, e := range mappingEntries(src) {
+				if !local[e.key.Value] {
+					add(e)
+				}
+			}
+		}
+	}
+	return entries
+}
+
+// mergeSources resolves the value of a "<<" key to the mappings it merges
+func mergeSources(value *yaml.Node) []*yaml.Node {
+	items := []*yaml.Node{value}
+	if value.Kind == yaml.SequenceNode {
+		items = value.Content
+	}
+	var sources []*yaml.Node
+	for _, src := range items {
+		if src.Kind == yaml.AliasNode {
+			src = src.Alias
+		}
+		if src.Kind == yaml.MappingNode {
+			sources = append(sources, src)
+		}
+	}
+	return sources
+}
+
+func isMergeKey(key *yaml.Node) bool {
+	return key.Value == "<<" && key.Tag == "!!merge"
+}
+
+// commentedYAML matches comment lines that look like disabled YAML ("password: old",
+// "- item: x") rather than prose
+var commentedYAML = regexp.MustCompile(`^(- )?[A-Za-z0-9_.\-/"']+:(\s|$)`)
+
+// leafComment returns the comments written above and after a leaf without their
+// "#" markers, one comment line per line. Lines that look like commented-out
+// YAML are left out, they often hold old values.
+func leafComment(key, leaf *yaml.Node) string {
+	var parts []string
+	for _, c := range []string{commentOf(key, true), leaf.HeadComment, leaf.LineComment, commentOf(key, false)} {
+		for _, line := range strings.Split(c, "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+			if line != "" && !commentedYAML.MatchString(line) {
+				parts = append(parts, line)
+			}
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func commentOf(key *yaml.Node, head bool) string {
+	switch {
+	case key == nil:
+		return ""
+	case head:
+		return key.HeadComment
+	}
+	return key.LineComment
+}
+
+// setLeafComment writes text as the head comment of a leaf (on its key in mappings)
+func setLeafComment(key, leaf *yaml.Node, text string) {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = "# " + line
+	}
+	target := leaf
+	if key != nil {
+		target = key
+	}
+	target.HeadComment = strings.Join(lines, "\n")
+}
+
+// documentRoot returns the top-level node of a parsed document
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// isNullNode reports whether a scalar is YAML null (~, null or nothing at all)
+func isNullNode(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null"
+}
+
+// yamlLeaf is one scalar of a YAML document, exactly as written, with the SSM path it maps to
+type yamlLeaf struct {
+	Path  string
+	Value string
+	Null  bool
+	Line  int
+	// Description is the comment on the leaf, see leafComment
+	Description string
+}
+
+// yamlLeaves parses raw and returns its leaves in document order. Scalars keep their
+// source text, so block scalars keep their newlines and dates or floats aren't reformatted.
+func yamlLeaves(raw []byte, prefix string) ([]yamlLeaf, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	var leaves []yamlLeaf
+	index := make(map[string]int)
+	walkYAMLLeaves(&doc, prefix, func(path string, key, leaf *yaml.Node) {
+		l := yamlLeaf{
+			Path:        path,
+			Value:       leaf.Value,
+			Null:        isNullNode(leaf),
+			Line:        leaf.Line,
+			Description: leafComment(key, leaf),
+		}
+		// Paths can still collide, e.g. a "db/host" key next to a db mapping: the
+		// later one wins, so a path is never uploaded twice
+		if i, ok := index[path]; ok {
+			leaves[i] = l
+			return
+		}
+		index[path] = len(leaves)
+		leaves = append(leaves, l)
+	})
+	return leaves, nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestYAMLLeaves(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want map[string]string
+	}{
+		{
+			name: "local key wins over merge",
+			yaml: "base: &a\n  token: base\n  host: h\nchild:\n  <<: *a\n  token: override\n",
+			want: map[string]string{"/p/base/token": "base", "/p/base/host": "h", "/p/child/token": "override", "/p/child/host": "h"},
+		},
+		{
+			name: "local key before the merge still wins",
+			yaml: "base: &a\n  token: base\nchild:\n  token: override\n  <<: *a\n",
+			want: map[string]string{"/p/base/token": "base", "/p/child/token": "override"},
+		},
+		{
+			name: "sequence merge, earlier source wins",
+			yaml: "a: &a\n  token: a\n  x: 1\nb: &b\n  token: b\n  y: 2\nmulti:\n  <<: [*a, *b]\n",
+			want: map[string]string{"/p/a/token": "a", "/p/a/x": "1", "/p/b/token": "b", "/p/b/y": "2", "/p/multi/token": "a", "/p/multi/x": "1", "/p/multi/y": "2"},
+		},
+		{
+			name: "nested merge",
+			yaml: "a: &a\n  x: 1\nb: &b\n  <<: *a\n  y: 2\nc:\n  <<: *b\n",
+			want: map[string]string{"/p/a/x": "1", "/p/b/x": "1", "/p/b/y": "2", "/p/c/x": "1", "/p/c/y": "2"},
+		},
+		{
+			name: "aliases, lists and block scalars",
+			yaml: "list:\n  - &v one\n  - *v\ncert: |\n  line1\n  line2\n",
+			want: map[string]string{"/p/list/0": "one", "/p/list/1": "one", "/p/cert": "line1\nline2\n"},
+		},
+		{
+			name: "metadata block is skipped",
+			yaml: "x-aws-ssm:\n  policies: {}\nkey: v\n",
+			want: map[string]string{"/p/key": "v"},
+		},
+		{
+			name: "colliding paths keep the later value",
+			yaml: "db:\n  host: a\ndb/host: b\n",
+			want: map[string]string{"/p/db/host": "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaves, err := yamlLeaves([]byte(tt.yaml), "/p")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			for _, l := range leaves {
+				if _, dup := got[l.Path]; dup {
+					t.Errorf("path %s returned twice", l.Path)
+				}
+				got[l.Path] = l.Value
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLLeavesNull(t *testing.T) {
+	leaves, err := yamlLeaves([]byte("a:\nb: ~\nc: \"\"\nd: x\n"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []bool{true, true, false, false}
+	for i, l := range leaves {
+		if l.Null != want[i] {
+			t.Errorf("%s: Null = %v, want %v", l.Path, l.Null, want[i])
+		}
+	}
+}
+
+func TestYAMLLeafDescriptions(t *testing.T) {
+	raw := `db:
+  # Primary database endpoint
+  host: db.internal
+  port: 5432  # PostgreSQL port
+  # password: old-secret
+  # Rotated monthly
+  password: new-secret
+  #user: admin
+  user: app
+`
+	leaves, err := yamlLeaves([]byte(raw), "/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, leaf := range leaves {
+		got[leaf.Path] = leaf.Description
+	}
+	want := map[string]string{
+		"/p/db/host":     "Primary database endpoint",
+		"/p/db/port":     "PostgreSQL port",
+		"/p/db/password": "Rotated monthly",
+		"/p/db/user":     "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("descriptions = %q, want %q", got, want)
+	}
+}
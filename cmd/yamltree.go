@@ -1,15 +1,13 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"sort"
 	"strings"
 
-	"github.com/fatih/color"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var yamlTreeCmd = &cobra.Command{
@@ -20,19 +18,24 @@ var yamlTreeCmd = &cobra.Command{
 		if yamlFile == "" {
 			return fmt.Errorf("--file is required")
 		}
+		if err := validateTreeFormat(); err != nil {
+			return err
+		}
 
 		rawYaml, err := os.ReadFile(yamlFile)
 		if err != nil {
 			return fmt.Errorf("error reading YAML file: %w", err)
 		}
 
-		var data map[string]interface{}
-		decoder := yaml.NewDecoder(bytes.NewReader(rawYaml))
-		if err := decoder.Decode(&data); err != nil {
+		if err := validateNullFlags(); err != nil {
+			return err
+		}
+		leaves, err := yamlLeaves(rawYaml, "")
+		if err != nil {
 			return fmt.Errorf("error parsing YAML: %w", err)
 		}
 
-		printYAMLTree(data)
+		printYAMLTree(leaves)
 		return nil
 	},
 }
@@ -40,98 +43,38 @@ var yamlTreeCmd = &cobra.Command{
 func init() {
 	yamlTreeCmd.Flags().StringVarP(&yamlFile, "file", "f", "", "YAML file to inspect (required)")
 	yamlTreeCmd.Flags().BoolVarP(&showValues, "values", "v", false, "Show values alongside keys")
+	yamlTreeCmd.Flags().BoolVar(&scanValues, "scan-values", false, "Also flag values that look like credentials as SecureString")
+	addTreeFilterFlags(yamlTreeCmd)
+	addNullFlags(yamlTreeCmd)
 }
 
-func printYAMLTree(data interface{}) {
-	isSensitiveKey := func(key string) bool {
-		keywords := []string{"password", "secret", "token", "key", "apikey", "auth", "private"}
-		key = strings.ToLower(key)
-		for _, word := range keywords {
-			if strings.Contains(key, word) {
-				return true
-			}
+// printYAMLTree renders the leaves of a YAML document through printTree, treating
+// secret-like keys as SecureString so both tree commands look (and filter) the same.
+// Values are shown as load would upload them; leaves --on-null skip drops are left out.
+func printYAMLTree(leaves []yamlLeaf) {
+	paths := make([]string, 0, len(leaves))
+	values := make(map[string]treeParam, len(leaves))
+	for _, leaf := range leaves {
+		fullPath := leaf.Path
+		value, upload := leafValue(leaf)
+		if !upload {
+			continue
 		}
-		return false
-	}
-
-	isLeaf := func(v interface{}) bool {
-		switch v.(type) {
-		case string, bool, int, int64, float64, float32, nil:
-			return true
-		default:
-			return false
+		paramType := types.ParameterTypeString
+		if flagged, _ := detector.classifyLeaf(fullPath, value, true, valueScanEnabled()); flagged {
+			paramType = types.ParameterTypeSecureString
 		}
+		values[fullPath] = treeParam{Type: paramType, Value: value}
+		paths = append(paths, strings.TrimPrefix(fullPath, "/"))
 	}
-
-	var walk func(node interface{}, prefix string, last bool, fullPath string)
-	walk = func(node interface{}, prefix string, last bool, fullPath string) {
-		connector := "├── "
-		if last {
-			connector = "└── "
-		}
-
-		switch v := node.(type) {
-		case map[string]interface{}:
-			keys := make([]string, 0, len(v))
-			for k := range v {
-				keys = append(keys, k)
-			}
-			//sort.Strings(keys)
-			sort.SliceStable(keys, func(i, j int) bool {
-				//return len(strings.Split(keys[i], "/")) < len(strings.Split(keys[j], "/"))
-				depthI := len(strings.Split(keys[i], "/"))
-				depthJ := len(strings.Split(keys[j], "/"))
-				if depthI != depthJ {
-					return depthI < depthJ
-				}
-				return keys[i] < keys[j] // fallback to alphabetical within same depth
-			})
-
-			for i, k := range keys {
-				isLast := i == len(keys)-1
-				newPrefix := prefix + map[bool]string{true: "    ", false: "│   "}[!isLast]
-				nextPath := fullPath + "/" + k
-				label := color.New(color.FgWhite).Sprint(k)
-
-				lock := ""
-				if isSensitiveKey(nextPath) {
-					lock = " 🔒"
-					label = color.New(color.FgCyan).Sprint(k)
-				}
-				if isLeaf(v[k]) && showValues {
-					value := fmt.Sprintf("%v", v[k])
-					fmt.Printf("%s%s%s%s = %s\n", prefix, connector, label, lock, color.New(color.FgHiBlack).Sprint(value))
-				} else {
-					fmt.Printf("%s%s%s%s\n", prefix, connector, label, lock)
-					walk(v[k], newPrefix, isLast, nextPath)
-				}
-			}
-
-		case []interface{}:
-			for i, item := range v {
-				isLast := i == len(v)-1
-				newPrefix := prefix + map[bool]string{true: "    ", false: "│   "}[!isLast]
-				nextPath := fmt.Sprintf("%s/%d", fullPath, i)
-				label := color.New(color.FgYellow).Sprintf("%d", i)
-
-				lock := ""
-				if isSensitiveKey(nextPath) {
-					lock = " 🔒"
-					label = color.New(color.FgCyan).Sprint(i)
-				}
-				if isLeaf(item) && showValues {
-					value := fmt.Sprintf("%v", item)
-					fmt.Printf("%s%s%s%s = %s\n", prefix, connector, label, lock, color.New(color.FgHiBlack).Sprint(value))
-				} else {
-					fmt.Printf("%s%s%s%s\n", prefix, connector, label, lock)
-					walk(item, newPrefix, isLast, nextPath)
-				}
-			}
-		}
+	sort.Strings(paths)
+
+	switch {
+	case jsonOutput():
+	case treeFormat == "text":
+		fmt.Println(style(roleRoot).Sprint("root"))
+	case treeFormat == "plain":
+		fmt.Println("root")
 	}
-
-	//fmt.Println(color.New(color.FgGreen, color.Bold).Sprint("└──"))
-	//walk(data, "    ", true, "")
-	fmt.Println(color.New(color.FgHiCyan, color.Bold).Sprint("root"))
-	walk(data, "", true, "")
+	printTree(paths, values, "")
 }
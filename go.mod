@@ -9,6 +9,7 @@ require (
 	github.com/aws/smithy-go v1.25.1
 	github.com/fatih/color v1.19.0
 	github.com/spf13/cobra v1.10.2
+	golang.org/x/term v0.41.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 